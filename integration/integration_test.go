@@ -5,11 +5,14 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -21,12 +24,17 @@ import (
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/column"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/conflict"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/expr"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/filter"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/formatter"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/functions"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/hooks"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/parser"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/sel"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/statement"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/stmtcache"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/table"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/window"
 )
 
 func isMySQL() bool {
@@ -321,6 +329,303 @@ func TestCreateTable_Defaults(t *testing.T) {
 	assert.Equal(t, colC, `foobar`)
 }
 
+func TestCreateTable_TimeDefaults(t *testing.T) {
+	db, b := getDatabaseAndBuilderWithoutTable(t)
+
+	fixed := time.Date(2024, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+	_, err := b.CreateTable(table.Named(`Test1`)).
+		Columns(
+			column.BigInt(`A`).PrimaryKey(),
+			column.DateTime(`CreatedAt`).NotNull().DefaultCurrentTimestamp(),
+			column.Timestamp(`UpdatedAt`).NotNull().DefaultExpr(functions.CurrentTimestamp()).OnUpdateCurrentTimestamp(),
+			column.DateTime(`Fixed`).Default(fixed),
+		).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named(`Test1`)).Columns(`A`).Values(1).Exec(db)
+	assert.NoError(t, err)
+
+	row, err := b.SelectFrom(
+		table.Named(`Test1`),
+	).Columns(
+		`CreatedAt`,
+		`UpdatedAt`,
+		`Fixed`,
+	).Where(filter.Equals(`A`, 1)).QueryRow(db)
+	assert.NoError(t, err)
+
+	var createdAt, updatedAt, fixedCol sql.NullString
+	assert.NoError(t, row.Scan(&createdAt, &updatedAt, &fixedCol))
+
+	assert.Equal(t, createdAt.Valid, true)
+	assert.Equal(t, updatedAt.Valid, true)
+	if isMySQL() {
+		assertNullableValueEquals(t, `2024-03-04 05:06:07`, fixedCol)
+	} else {
+		assertNullableValueEquals(t, `2024-03-04T05:06:07.000Z`, fixedCol)
+	}
+
+	// Bound times compare with the stored defaults, whatever their zone.
+	count := func(f filter.Filter) int {
+		row, err := b.SelectFrom(table.Named(`Test1`)).
+			Expressions(functions.CountAll()).
+			Where(f).
+			QueryRow(db)
+		assert.NoError(t, err)
+		var n int
+		assert.NoError(t, row.Scan(&n))
+		return n
+	}
+	assert.Equal(t, count(filter.Equals(`Fixed`, fixed)), 1)
+	assert.Equal(t, count(filter.Equals(`Fixed`, fixed.In(time.FixedZone(``, 3600)))), 1)
+	assert.Equal(t, count(filter.Less(`Fixed`, fixed.Add(500*time.Millisecond))), 1)
+	assert.Equal(t, count(filter.Greater(`Fixed`, fixed)), 0)
+	assert.Equal(t, count(filter.LessOrEqual(`CreatedAt`, time.Now().Add(time.Minute))), 1)
+	assert.Equal(t, count(filter.Greater(`CreatedAt`, time.Now().Add(-time.Minute))), 1)
+}
+
+func TestGeneratedColumns(t *testing.T) {
+	db, b := getDatabaseAndBuilderWithoutTable(t)
+
+	cols := []column.Builder{
+		column.BigInt(`A`).PrimaryKey(),
+		column.VarChar(`B`, 32),
+		column.VarChar(`C`, 32).GeneratedAs(column.Named(`B`), true),
+		column.VarChar(`D`, 32).GeneratedAs(column.Named(`B`), false),
+	}
+	tbl := table.Named(`Test1`).WithColumns(cols...)
+
+	_, err := b.CreateTable(tbl).Columns(cols...).Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(tbl).Columns(`A`, `B`).Values(1, `foo`).Exec(db)
+	assert.NoError(t, err)
+
+	row, err := b.SelectFrom(tbl).Columns(`C`, `D`).Where(filter.Equals(`A`, 1)).QueryRow(db)
+	assert.NoError(t, err)
+
+	var c, d string
+	assert.NoError(t, row.Scan(&c, &d))
+	assert.Equal(t, c, `foo`)
+	assert.Equal(t, d, `foo`)
+
+	_, err = b.InsertInto(tbl).Columns(`A`, `B`, `C`).Values(2, `bar`, `baz`).Build()
+	assert.Error(t, err)
+
+	_, err = b.InsertInto(tbl).
+		Columns(`A`, `B`).
+		Values(1, `bar`).
+		OnConflict(conflict.NewKey(`A`), conflict.Overwrite(`D`)).
+		Build()
+	assert.Error(t, err)
+
+	// Column names are case-insensitive, and aliasing the table keeps its schema.
+	_, err = b.InsertInto(tbl).Columns(`A`, `c`).Values(2, `baz`).Build()
+	assert.Error(t, err)
+	_, err = b.InsertInto(tbl.As(`t`)).Columns(`A`, `C`).Values(2, `baz`).Build()
+	assert.Error(t, err)
+}
+
+func TestCreateTable_Options(t *testing.T) {
+	db, b := getDatabaseAndBuilderWithoutTable(t)
+
+	create := b.CreateTable(table.Named(`Test1`)).
+		Columns(
+			column.VarChar(`A`, 32).PrimaryKey(),
+			column.VarChar(`B`, 32).Collate(`NOCASE`),
+		)
+	if isMySQL() {
+		create = b.CreateTable(table.Named(`Test1`)).
+			Columns(
+				column.VarChar(`A`, 32).PrimaryKey().Comment(`the key`),
+				column.VarChar(`B`, 32).Collate(`utf8mb4_general_ci`),
+			).
+			Engine(`InnoDB`).
+			DefaultCharset(`utf8mb4`).
+			Collate(`utf8mb4_bin`).
+			Comment(`a table with options`)
+	} else {
+		create.WithoutRowID().Strict()
+	}
+
+	_, err := create.Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named(`Test1`)).
+		Columns(`A`, `B`).
+		Values(`a`, `FOO`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	// B has a case-insensitive collation
+	row, err := b.SelectFrom(table.Named(`Test1`)).
+		Columns(`A`).
+		Where(filter.Equals(`B`, `foo`)).
+		QueryRow(db)
+	assert.NoError(t, err)
+
+	var a string
+	assert.NoError(t, row.Scan(&a))
+	assert.Equal(t, a, `a`)
+
+	// Options for the other dialect are rejected rather than dropped.
+	unsupported := b.CreateTable(table.Named(`Test2`)).Columns(column.Int(`A`))
+	if isMySQL() {
+		unsupported.Strict()
+	} else {
+		unsupported.Engine(`InnoDB`)
+	}
+	_, err = unsupported.Build()
+	assert.Error(t, err)
+}
+
+func TestCreateTable_AsSelect(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `aa`).
+		Values(`b`, 2, `bb`).
+		Values(`c`, 3, `cc`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.CreateTable(table.Named(`Copy`)).
+		IfNotExists().
+		AsSelect(
+			b.SelectFrom(table.Named(`Example`)).
+				Columns(`ID`, `TextField`).
+				Where(filter.GreaterOrEqual(`NumberField`, 2)),
+		).
+		Exec(db)
+	assert.NoError(t, err)
+
+	rows, err := b.SelectFrom(table.Named(`Copy`)).
+		Columns(`ID`, `TextField`).
+		OrderBy(filter.OrderAsc(`ID`)).
+		Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	var id, text string
+	assert.Equal(t, rows.Next(), true)
+	assert.NoError(t, rows.Scan(&id, &text))
+	assert.Equal(t, id, `b`)
+	assert.Equal(t, text, `bb`)
+
+	assert.Equal(t, rows.Next(), true)
+	assert.NoError(t, rows.Scan(&id, &text))
+	assert.Equal(t, id, `c`)
+	assert.Equal(t, text, `cc`)
+
+	assert.Equal(t, rows.Next(), false)
+}
+
+func TestCreateTable_Like(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.CreateTable(table.Named(`Shadow`)).
+		LikeTable(table.Named(`Example`)).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.CreateTable(table.Named(`Shadow`)).
+		IfNotExists().
+		LikeTable(table.Named(`Example`)).
+		ExecContext(context.Background(), db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named(`Shadow`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `aa`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	// The primary key was copied too.
+	_, err = b.InsertInto(table.Named(`Shadow`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 2, `bb`).
+		Exec(db)
+	assert.Error(t, err)
+
+	_, err = b.CreateTable(table.Named(`Shadow2`)).
+		LikeTable(table.Named(`DoesNotExist`)).
+		Exec(db)
+	assert.Error(t, err)
+
+	// Mistakes in the statement itself are reported as they are, without reading any definitions.
+	_, err = b.CreateTable(table.Named(`Shadow2`)).
+		LikeTable(table.Named(`Example`)).
+		Columns(column.Int(`ID`)).
+		Exec(db)
+	be, ok := err.(*sqlbuilder.BuildError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, be.Clause, `CREATE TABLE`)
+
+	if !isMySQL() {
+		// A qualified table's definition is read from its schema's sqlite_master.
+		_, err = b.CreateTable(table.Named(`Shadow3`)).
+			LikeTable(table.Named(`Example`).Qualified(`main`)).
+			Exec(db)
+		assert.NoError(t, err)
+	}
+}
+
+func TestViews(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `aa`).
+		Values(`b`, 2, `bb`).
+		Values(`c`, 3, `cc`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	report := table.Named(`Report`)
+
+	// Views can't have placeholders.
+	_, err = b.CreateView(report).
+		As(b.SelectFrom(table.Named(`Example`)).Columns(`ID`).Where(filter.Equals(`NumberField`, 1))).
+		Build()
+	assert.Error(t, err)
+
+	_, err = b.CreateView(report).
+		Columns(`Total`).
+		As(b.SelectFrom(table.Named(`Example`)).Expressions(functions.CountAll())).
+		Exec(db)
+	assert.NoError(t, err)
+
+	recreate := b.CreateView(report).
+		Columns(`Total`).
+		As(b.SelectFrom(table.Named(`Example`)).Expressions(functions.CountColumn(`TextField`)))
+	if isMySQL() {
+		recreate.OrReplace()
+	} else {
+		recreate.IfNotExists()
+	}
+	_, err = recreate.Exec(db)
+	assert.NoError(t, err)
+
+	row, err := b.SelectFrom(report).Columns(`Total`).QueryRow(db)
+	assert.NoError(t, err)
+
+	var total int
+	assert.NoError(t, row.Scan(&total))
+	assert.Equal(t, total, 3)
+
+	_, err = b.DropView(report).Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.DropView(report).Exec(db)
+	assert.Error(t, err)
+
+	_, err = b.DropView(report).IfExists().Exec(db)
+	assert.NoError(t, err)
+}
+
 func TestCount(t *testing.T) {
 	db, b := getDatabaseAndBuilderWithoutTable(t)
 
@@ -582,7 +887,7 @@ func TestInsertBatches(t *testing.T) {
 
 		assert.Equal(t, i, len(exp))
 
-		res, err := b.DeleteFrom(table.Named(`Example`)).Exec(db)
+		res, err := b.DeleteFrom(table.Named(`Example`)).All().Exec(db)
 		assert.NoError(t, err)
 
 		n, err := res.RowsAffected()
@@ -954,100 +1259,915 @@ func TestBasicFunction(t *testing.T) {
 	}
 }
 
-func TestJoins(t *testing.T) {
-	db, b := getDatabaseAndBuilderWithoutTable(t)
-
-	_, err := b.CreateTable(table.Named("TableA")).Columns(
-		column.VarChar("IDA", 32),
-		column.Int("NumA"),
-	).Exec(db)
-	assert.NoError(t, err)
-
-	_, err = b.CreateTable(table.Named("TableB")).Columns(
-		column.VarChar("IDB", 32),
-		column.Int("NumB"),
-	).Exec(db)
-	assert.NoError(t, err)
+func TestFunctionLibrary(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
 
-	_, err = b.InsertInto(table.Named("TableA")).
-		Columns("IDA", "NumA").
-		Values("a", 1).
-		Values("b", 2).
-		Values("c", 3).
-		Values("d", 4).
-		Values("e", 5).
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `Apple`).
+		Values(`b`, -2, `banana`).
+		Values(`c`, 3, nil).
+		Values(`d`, 4, `Ünïcode`).
+		Values(`e`, 4, `elderberry`).
 		Exec(db)
 	assert.NoError(t, err)
 
-	_, err = b.InsertInto(table.Named("TableB")).
-		Columns("IDB", "NumB").
-		Values("f", 2).
-		Values("g", 3).
-		Values("h", 4).
-		Values("i", 5).
-		Values("j", 6).
-		Exec(db)
-	assert.NoError(t, err)
+	var (
+		id   = column.Named(`ID`)
+		num  = column.Named(`NumberField`)
+		text = column.Named(`TextField`)
+	)
 
-	rows, err := b.SelectFrom(
-		table.Named("TableA").
-			As("my_table").
-			InnerJoin(table.Named("TableB")).
-			OnEqualExpressions(
-				column.Named("NumA").QualifiedBy("my_table"),
-				column.Named("NumB"),
-			),
-	).Expressions(
-		column.Named("IDA"),
-		column.Named("IDB"),
-		column.Named("NumA").QualifiedBy("my_table"),
-		column.Named("NumB"),
-	).OrderBy(
-		filter.OrderAsc("IDA"),
-	).Query(db)
+	row, err := b.SelectFrom(table.Named(`Example`)).
+		Expressions(
+			functions.Sum(num),
+			functions.Min(num),
+			functions.Max(num),
+			functions.Avg(num),
+			functions.GroupConcat(id).Separator(`|`).OrderBy(filter.OrderDesc(`ID`)),
+			functions.GroupConcat(num).Distinct().OrderBy(filter.OrderAsc(`NumberField`)),
+		).
+		QueryRow(db)
 	assert.NoError(t, err)
-	cleanupRows(t, rows)
 
 	{
 		var (
-			idA  string
-			idB  string
-			numA int
-			numB int
+			sum, min, max int
+			avg           float64
+			ids, nums     string
 		)
+		assert.NoError(t, row.Scan(&sum, &min, &max, &avg, &ids, &nums))
+		assert.Equal(t, sum, 10)
+		assert.Equal(t, min, -2)
+		assert.Equal(t, max, 4)
+		assert.Equal(t, avg, 2.0)
+		assert.Equal(t, ids, `e|d|c|b|a`)
+		assert.Equal(t, nums, `-2,1,3,4`)
+	}
 
-		assert.Equal(t, rows.Next(), true)
-		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
-		assert.Equal(t, idA, `b`)
-		assert.Equal(t, idB, `f`)
-		assert.Equal(t, numA, 2)
-		assert.Equal(t, numB, 2)
+	selectString := func(t *testing.T, rowID string, e functions.Call) sql.NullString {
+		t.Helper()
 
-		assert.Equal(t, rows.Next(), true)
-		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
-		assert.Equal(t, idA, `c`)
-		assert.Equal(t, idB, `g`)
-		assert.Equal(t, numA, 3)
-		assert.Equal(t, numB, 3)
+		row, err := b.SelectFrom(table.Named(`Example`)).
+			Expressions(e).
+			Where(filter.Equals(`ID`, rowID)).
+			QueryRow(db)
+		assert.NoError(t, err)
 
-		assert.Equal(t, rows.Next(), true)
-		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
-		assert.Equal(t, idA, `d`)
-		assert.Equal(t, idB, `h`)
-		assert.Equal(t, numA, 4)
-		assert.Equal(t, numB, 4)
+		var res sql.NullString
+		assert.NoError(t, row.Scan(&res))
+		return res
+	}
 
-		assert.Equal(t, rows.Next(), true)
-		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
-		assert.Equal(t, idA, `e`)
-		assert.Equal(t, idB, `i`)
-		assert.Equal(t, numA, 5)
-		assert.Equal(t, numB, 5)
+	assert.Equal(t, selectString(t, `c`, functions.Coalesce(text, filter.Value(`none`))).String, `none`)
+	assert.Equal(t, selectString(t, `c`, functions.IfNull(text, filter.Value(`none`))).String, `none`)
+	assert.Equal(t, selectString(t, `a`, functions.IfNull(text, filter.Value(`none`))).String, `Apple`)
+	assert.Equal(t, selectString(t, `a`, functions.NullIf(num, filter.Value(1))).Valid, false)
+	assert.Equal(t, selectString(t, `b`, functions.NullIf(num, filter.Value(1))).String, `-2`)
+	assert.Equal(t, selectString(t, `a`, functions.Lower(text)).String, `apple`)
+	assert.Equal(t, selectString(t, `a`, functions.Upper(text)).String, `APPLE`)
+	assert.Equal(t, selectString(t, `d`, functions.Length(text)).String, `7`)
+	assert.Equal(t, selectString(t, `b`, functions.Substr(text, 2, 3)).String, `ana`)
+	assert.Equal(t, selectString(t, `b`, functions.SubstrFrom(text, 4)).String, `ana`)
+	assert.Equal(t, selectString(t, `a`, functions.Concat(id, filter.Value(`-`), functions.Lower(text))).String, `a-apple`)
+	assert.Equal(t, selectString(t, `b`, functions.Abs(num)).String, `2`)
 
-		assert.Equal(t, rows.Next(), false)
+	row, err = b.SelectFrom(table.Named(`Example`)).
+		Expressions(functions.Round(filter.Value(2.567), 1)).
+		Where(filter.Equals(`ID`, `a`)).
+		QueryRow(db)
+	assert.NoError(t, err)
+
+	var rounded float64
+	assert.NoError(t, row.Scan(&rounded))
+	assert.Equal(t, rounded, 2.6)
+
+	// Functions can be used in filters.
+	rows, err := b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		Where(filter.ExprEquals(functions.Lower(text), filter.Value(`apple`))).
+		Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		assert.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
 	}
+	assert.Equal(t, ids, []string{`a`})
+}
 
-	rows, err = b.SelectFrom(
+func TestTimeFunctions(t *testing.T) {
+	db, b := getDatabaseAndBuilderWithoutTable(t)
+
+	_, err := b.CreateTable(table.Named(`Times`)).
+		Columns(
+			column.Int(`ID`).PrimaryKey(),
+			column.DateTime(`T`),
+		).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named(`Times`)).
+		Columns(`ID`, `T`).
+		Values(1, time.Date(2024, time.March, 15, 13, 45, 30, 0, time.UTC)).
+		Exec(db)
+	assert.NoError(t, err)
+
+	tc := column.Named(`T`)
+
+	row, err := b.SelectFrom(table.Named(`Times`)).
+		Expressions(
+			functions.Extract(functions.Year, tc),
+			functions.Extract(functions.Month, tc),
+			functions.Extract(functions.Day, tc),
+			functions.Extract(functions.Hour, tc),
+			functions.Extract(functions.Minute, tc),
+			functions.Extract(functions.Second, tc),
+		).
+		QueryRow(db)
+	assert.NoError(t, err)
+
+	var year, month, day, hour, minute, second int
+	assert.NoError(t, row.Scan(&year, &month, &day, &hour, &minute, &second))
+	assert.Equal(t, []int{year, month, day, hour, minute, second}, []int{2024, 3, 15, 13, 45, 30})
+
+	count := func(t *testing.T, f filter.Filter) int {
+		t.Helper()
+
+		row, err := b.SelectFrom(table.Named(`Times`)).
+			Expressions(functions.CountAll()).
+			Where(f).
+			QueryRow(db)
+		assert.NoError(t, err)
+
+		var n int
+		assert.NoError(t, row.Scan(&n))
+		return n
+	}
+
+	truncated := []struct {
+		unit functions.TimeUnit
+		exp  time.Time
+	}{
+		{functions.Year, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{functions.Month, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{functions.Day, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{functions.Hour, time.Date(2024, time.March, 15, 13, 0, 0, 0, time.UTC)},
+		{functions.Minute, time.Date(2024, time.March, 15, 13, 45, 0, 0, time.UTC)},
+	}
+	for _, tr := range truncated {
+		assert.Equal(t, count(t, filter.ExprEquals(functions.TruncTime(tr.unit, tc), filter.Value(tr.exp))), 1)
+	}
+
+	assert.Equal(t, count(t, filter.ExprLess(tc, functions.Now())), 1)
+	assert.Equal(t, count(t, filter.ExprGreater(tc, functions.Now())), 0)
+}
+
+func TestCaseExpressions(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `small`).
+		Values(`b`, 2, `small`).
+		Values(`c`, 3, `big`).
+		Values(`d`, 4, `big`).
+		Values(`e`, 5, `big`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	num := column.Named(`NumberField`)
+
+	// Conditional counting
+	row, err := b.SelectFrom(table.Named(`Example`)).
+		Expressions(
+			functions.Sum(expr.Case().When(filter.Greater(`NumberField`, 2), 1).Else(0)),
+			functions.Sum(expr.CaseOf(column.Named(`TextField`)).When(`small`, num).Else(0)),
+		).
+		QueryRow(db)
+	assert.NoError(t, err)
+
+	var bigCount, smallSum int
+	assert.NoError(t, row.Scan(&bigCount, &smallSum))
+	assert.Equal(t, bigCount, 3)
+	assert.Equal(t, smallSum, 3)
+
+	// Custom sort order, with placeholders in WHERE, ORDER BY and LIMIT
+	rows, err := b.SelectFrom(table.Named(`Example`)).
+		Expressions(
+			column.Named(`ID`),
+			expr.Case().
+				When(filter.Less(`NumberField`, 2), `low`).
+				When(filter.Less(`NumberField`, 4), `mid`).
+				Else(`high`),
+		).
+		Where(filter.NotEquals(`ID`, `b`)).
+		OrderBy(filter.OrderExprAsc(expr.CaseOf(column.Named(`ID`)).When(`d`, 0).When(`a`, 1).Else(2))).
+		Limit(3).
+		Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	type result struct {
+		ID, Size string
+	}
+	var results []result
+	for rows.Next() {
+		var r result
+		assert.NoError(t, rows.Scan(&r.ID, &r.Size))
+		results = append(results, r)
+	}
+	assert.Equal(t, len(results), 3)
+	assert.Equal(t, results[0], result{`d`, `high`})
+	assert.Equal(t, results[1], result{`a`, `low`})
+	assert.Equal(t, results[2].ID == `c` || results[2].ID == `e`, true)
+
+	// UPDATE assignments
+	_, err = b.Update(table.Named(`Example`)).
+		SetFieldToExpr(`TextField`, expr.Case().When(filter.Equals(`NumberField`, 3), `three`).Else(column.Named(`TextField`))).
+		Where(filter.In(`ID`, `b`, `c`)).
+		Exec(db)
+	assert.NoError(t, err)
+
+	rows, err = b.SelectFrom(table.Named(`Example`)).
+		Columns(`TextField`).
+		OrderBy(filter.OrderAsc(`ID`)).
+		Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	var texts []string
+	for rows.Next() {
+		var text string
+		assert.NoError(t, rows.Scan(&text))
+		texts = append(texts, text)
+	}
+	assert.Equal(t, texts, []string{`small`, `small`, `three`, `big`, `big`})
+}
+
+func TestWindowFunctions(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `x`).
+		Values(`b`, 2, `x`).
+		Values(`c`, 4, `y`).
+		Values(`d`, 4, `y`).
+		Values(`e`, 5, `y`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	var (
+		id   = column.Named(`ID`)
+		num  = column.Named(`NumberField`)
+		text = column.Named(`TextField`)
+	)
+
+	byRow := window.Over().PartitionBy(text).OrderBy(filter.OrderAsc(`NumberField`), filter.OrderAsc(`ID`))
+
+	q := b.SelectFrom(table.Named(`Example`)).
+		Expressions(
+			id,
+			functions.RowNumber().Over(byRow),
+			functions.Rank().OverWindow(`w`),
+			functions.DenseRank().OverWindow(`w`),
+			functions.Lag(id, 1).Over(byRow),
+			functions.Lead(id, 1).Over(byRow),
+			functions.FirstValue(id).Over(byRow),
+			functions.CountAll().Over(window.Over().PartitionBy(text)),
+			functions.Sum(num).Over(window.Over().OrderBy(filter.OrderAsc(`ID`)).Rows(window.Preceding(1), window.CurrentRow())),
+		).
+		Window(`w`, window.Over().PartitionBy(text).OrderBy(filter.OrderAsc(`NumberField`))).
+		OrderBy(filter.OrderAsc(`ID`))
+
+	if isMySQL() {
+		// We test against MySQL 5.7, which doesn't have window functions.
+		_, err := q.Build()
+		assert.Error(t, err)
+		return
+	}
+
+	rows, err := q.Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	type result struct {
+		ID                     string
+		RowNumber, Rank, Dense int
+		Lag, Lead              sql.NullString
+		First                  string
+		Count, MovingSum       int
+	}
+
+	var results []result
+	for rows.Next() {
+		var r result
+		assert.NoError(t, rows.Scan(&r.ID, &r.RowNumber, &r.Rank, &r.Dense, &r.Lag, &r.Lead, &r.First, &r.Count, &r.MovingSum))
+		results = append(results, r)
+	}
+
+	str := func(s string) sql.NullString { return sql.NullString{String: s, Valid: s != ``} }
+	assert.Equal(t, results, []result{
+		{ID: `a`, RowNumber: 1, Rank: 1, Dense: 1, Lag: str(``), Lead: str(`b`), First: `a`, Count: 2, MovingSum: 1},
+		{ID: `b`, RowNumber: 2, Rank: 2, Dense: 2, Lag: str(`a`), Lead: str(``), First: `a`, Count: 2, MovingSum: 3},
+		{ID: `c`, RowNumber: 1, Rank: 1, Dense: 1, Lag: str(``), Lead: str(`d`), First: `c`, Count: 3, MovingSum: 6},
+		{ID: `d`, RowNumber: 2, Rank: 1, Dense: 1, Lag: str(`c`), Lead: str(`e`), First: `c`, Count: 3, MovingSum: 8},
+		{ID: `e`, RowNumber: 3, Rank: 3, Dense: 2, Lag: str(`d`), Lead: str(``), First: `c`, Count: 3, MovingSum: 9},
+	})
+}
+
+func TestAliases(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `x`).
+		Values(`b`, 2, `x`).
+		Values(`c`, 3, `y`).
+		Values(`d`, 4, `y`).
+		Values(`e`, 5, `y`).
+		Values(`f`, 6, `z`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	rows, err := b.SelectFrom(table.Named(`Example`)).
+		Expressions(
+			column.Named(`TextField`).As(`txt`),
+			functions.CountAll().As(`n`),
+			functions.Sum(column.Named(`NumberField`)).As(`total`),
+			expr.Case().When(filter.Greater(`TextField`, `x`), `late`).Else(`early`).As(`half`),
+		).
+		GroupBy(`TextField`).
+		Having(filter.Greater(`n`, 1)).
+		OrderBy(filter.OrderDesc(`n`)).
+		Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	type group struct {
+		Text  string
+		N     int
+		Total int
+		Half  string
+	}
+	var groups []group
+	for rows.Next() {
+		var g group
+		assert.NoError(t, rows.Scan(&g.Text, &g.N, &g.Total, &g.Half))
+		groups = append(groups, g)
+	}
+	assert.Equal(t, groups, []group{
+		{Text: `y`, N: 3, Total: 12, Half: `late`},
+		{Text: `x`, N: 2, Total: 3, Half: `early`},
+	})
+
+	// Placeholders inside aliased expressions keep their order.
+	row, err := b.SelectFrom(table.Named(`Example`).As(`e`)).
+		Expressions(
+			column.Named(`ID`).QualifiedBy(`e`).As(`id`),
+			b.SelectFrom(table.Named(`Example`)).
+				Expressions(functions.CountAll()).
+				Where(filter.Greater(`NumberField`, 2)).
+				As(`bigger`),
+		).
+		Where(filter.Equals(`ID`, `a`)).
+		QueryRow(db)
+	assert.NoError(t, err)
+
+	var (
+		id     string
+		bigger int
+	)
+	assert.NoError(t, row.Scan(&id, &bigger))
+	assert.Equal(t, id, `a`)
+	assert.Equal(t, bigger, 4)
+
+	// Arguments are bound in the order their placeholders are written in: the aliased expressions',
+	// then the join condition's, then the WHERE clause's.
+	ref := column.NewRef[string](`ID`)
+	stmt, err := b.SelectFrom(
+		table.Named(`Example`).
+			As(`e1`).
+			InnerJoin(table.Named(`Example`).As(`e2`)).
+			On(ref.QualifiedBy(`e2`).Eq(`b`).IntoExpr()),
+	).
+		Expressions(expr.Case().When(ref.QualifiedBy(`e1`).Eq(`a`), `first`).Else(`other`).As(`which`)).
+		Where(ref.QualifiedBy(`e1`).Eq(`a`)).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, stmt.Args, []any{`a`, `first`, `other`, `b`, `a`})
+
+	var which string
+	assert.NoError(t, db.QueryRow(stmt.Stmt, stmt.Args...).Scan(&which))
+	assert.Equal(t, which, `first`)
+}
+
+func TestRawExpressions(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `it's`).
+		Values(`b`, 2, `x`).
+		Values(`c`, 3, `y`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.Update(table.Named(`Example`)).
+		SetFieldToExpr(`NumberField`, expr.Raw(`NumberField * ?`, 10)).
+		Where(expr.Raw(`ID = ?`, `c`)).
+		Exec(db)
+	assert.NoError(t, err)
+
+	rows, err := b.SelectFrom(table.Named(`Example`)).
+		Expressions(
+			column.Named(`ID`),
+			expr.Raw(`NumberField + ?`, 100).As(`bumped`),
+		).
+		Where(filter.All(
+			filter.GreaterOrEqual(`NumberField`, 1),
+			// Parenthesized, so the OR doesn't escape.
+			expr.Raw(`TextField = 'it''s?' OR NumberField > ?`, 1),
+		)).
+		OrderBy(filter.OrderExprDesc(expr.Raw(`NumberField % ?`, 7))).
+		Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	type result struct {
+		ID     string
+		Bumped int
+	}
+	var results []result
+	for rows.Next() {
+		var r result
+		assert.NoError(t, rows.Scan(&r.ID, &r.Bumped))
+		results = append(results, r)
+	}
+	assert.Equal(t, results, []result{{`b`, 102}, {`c`, 130}})
+
+	// Raw table expressions and join conditions
+	row, err := b.SelectFrom(
+		table.Named(`Example`).
+			As(`e1`).
+			InnerJoin(expr.Raw(`(SELECT ID, NumberField FROM Example WHERE NumberField > ?) AS e2`, 1)).
+			On(expr.Raw(`e1.NumberField = e2.NumberField AND e2.ID != ?`, `z`).IntoExpr()),
+	).
+		Expressions(functions.CountAll()).
+		QueryRow(db)
+	assert.NoError(t, err)
+
+	var n int
+	assert.NoError(t, row.Scan(&n))
+	assert.Equal(t, n, 2)
+
+	_, err = b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		Where(expr.Raw(`ID = ? OR ID = ?`, `a`)).
+		Build()
+	assert.Error(t, err)
+
+	// Arguments are bound in the order their placeholders appear, so the selected expressions' come
+	// before the join condition's.
+	stmt, err := b.SelectFrom(
+		table.Named(`Example`).
+			As(`e1`).
+			InnerJoin(table.Named(`Example`).As(`e2`)).
+			On(expr.Raw(`e1.ID = ?`, `ON`).IntoExpr()),
+	).
+		Expressions(expr.Raw(`?`, `COL`)).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, stmt.Args, []any{`COL`, `ON`})
+}
+
+func TestSearchFilters(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `apple`).
+		Values(`b`, 2, `banana`).
+		Values(`c`, 3, `100% apple_juice`).
+		Values(`d`, 4, `1000 apples`).
+		Values(`e`, 5, `cherry`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	selectIDs := func(t *testing.T, f filter.Filter) []string {
+		t.Helper()
+
+		rows, err := b.SelectFrom(table.Named(`Example`)).
+			Columns(`ID`).
+			Where(f).
+			OrderBy(filter.OrderAsc(`ID`)).
+			Query(db)
+		assert.NoError(t, err)
+		cleanupRows(t, rows)
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			assert.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	assert.Equal(t, selectIDs(t, filter.Like(`TextField`, `%apple%`)), []string{`a`, `c`, `d`})
+	assert.Equal(t, selectIDs(t, filter.NotLike(`TextField`, `%apple%`)), []string{`b`, `e`})
+	assert.Equal(t, selectIDs(t, filter.Like(`TextField`, `100!%%`).Escape('!')), []string{`c`})
+
+	// Wildcards in user input are matched literally.
+	assert.Equal(t, selectIDs(t, filter.StartsWith(`TextField`, `100%`)), []string{`c`})
+	assert.Equal(t, selectIDs(t, filter.Contains(`TextField`, `apple_`)), []string{`c`})
+	assert.Equal(t, selectIDs(t, filter.EndsWith(`TextField`, `apples`)), []string{`d`})
+	assert.Equal(t, selectIDs(t, filter.Contains(`TextField`, `an`)), []string{`b`})
+
+	assert.Equal(t, selectIDs(t, filter.Between(`NumberField`, 2, 4)), []string{`b`, `c`, `d`})
+	assert.Equal(t, selectIDs(t, filter.NotBetween(`NumberField`, 2, 4)), []string{`a`, `e`})
+	assert.Equal(t, selectIDs(t, filter.NotIn(`ID`, `a`, `c`, `e`)), []string{`b`, `d`})
+	assert.Equal(t,
+		selectIDs(t, filter.Not(filter.Any(filter.Equals(`ID`, `a`), filter.Greater(`NumberField`, 3)))),
+		[]string{`b`, `c`},
+	)
+}
+
+func TestEmptyFilters(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`).
+		Values(`a`, 1).
+		Values(`b`, 2).
+		Values(`c`, 3).
+		Exec(db)
+	assert.NoError(t, err)
+
+	selectIDs := func(t *testing.T, q *sel.Builder) []string {
+		t.Helper()
+
+		rows, err := q.OrderBy(filter.OrderAsc(`ID`)).Query(db)
+		assert.NoError(t, err)
+		cleanupRows(t, rows)
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			assert.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	query := func() *sel.Builder {
+		return b.SelectFrom(table.Named(`Example`)).Columns(`ID`)
+	}
+
+	assert.Equal(t, selectIDs(t, query().Where(filter.All())), []string{`a`, `b`, `c`})
+	assert.Equal(t, selectIDs(t, query().Where(filter.Any())), nil)
+	assert.Equal(t, selectIDs(t, query().Where(filter.In[string](`ID`))), nil)
+	assert.Equal(t, selectIDs(t, query().Where(filter.NotIn[string](`ID`))), []string{`a`, `b`, `c`})
+	assert.Equal(t, selectIDs(t, query().Where(filter.Not(filter.Any()))), []string{`a`, `b`, `c`})
+	assert.Equal(t,
+		selectIDs(t, query().WhereAny(filter.In[string](`ID`), filter.Equals(`ID`, `b`))),
+		[]string{`b`},
+	)
+
+	// Filters can be added incrementally.
+	q := query().Where(filter.Greater(`NumberField`, 1))
+	q.AndWhere(filter.NotIn[string](`ID`))
+	q.AndWhere(filter.Less(`NumberField`, 3))
+	assert.Equal(t, selectIDs(t, q), []string{`b`})
+
+	q = query()
+	q.AndWhere()
+	q.AndWhere(filter.NotEquals(`ID`, `a`))
+	assert.Equal(t, selectIDs(t, q), []string{`b`, `c`})
+
+	// An always-true condition is dropped altogether.
+	stmt, err := query().Where(filter.All(filter.NotIn[string](`ID`))).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Contains(stmt.Stmt, `WHERE`), false)
+}
+
+func TestTupleFilters(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`).
+		Values(`a`, 1).
+		Values(`a2`, 2).
+		Values(`b`, 1).
+		Values(`b2`, 2).
+		Values(`c`, 3).
+		Exec(db)
+	assert.NoError(t, err)
+
+	selectIDs := func(t *testing.T, f filter.Filter) []string {
+		t.Helper()
+
+		rows, err := b.SelectFrom(table.Named(`Example`)).
+			Columns(`ID`).
+			Where(f).
+			OrderBy(filter.OrderAsc(`ID`)).
+			Query(db)
+		assert.NoError(t, err)
+		cleanupRows(t, rows)
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			assert.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	cols := []string{`ID`, `NumberField`}
+
+	assert.Equal(t,
+		selectIDs(t, filter.TupleIn(cols, []any{`a`, 1}, []any{`b2`, 2}, []any{`c`, 4})),
+		[]string{`a`, `b2`},
+	)
+	assert.Equal(t,
+		selectIDs(t, filter.TupleNotIn(cols, []any{`a`, 1}, []any{`b2`, 2})),
+		[]string{`a2`, `b`, `c`},
+	)
+	assert.Equal(t,
+		selectIDs(t, filter.All(filter.TupleIn(cols, []any{`a`, 1}, []any{`a2`, 2}), filter.Equals(`NumberField`, 2))),
+		[]string{`a2`},
+	)
+	assert.Equal(t, selectIDs(t, filter.TupleIn(cols)), nil)
+
+	assert.Equal(t, selectIDs(t, filter.TupleEquals(cols, `b`, 1)), []string{`b`})
+	assert.Equal(t, selectIDs(t, filter.TupleGreater([]string{`NumberField`, `ID`}, 1, `a`)), []string{`a2`, `b`, `b2`, `c`})
+	assert.Equal(t, selectIDs(t, filter.TupleLessOrEqual([]string{`NumberField`, `ID`}, 2, `a2`)), []string{`a`, `a2`, `b`})
+
+	_, err = b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		Where(filter.TupleIn(cols, []any{`a`})).
+		Build()
+	assert.Error(t, err)
+}
+
+// TestFilterPrecedence builds random trees of filters and checks that the database agrees with a Go
+// evaluation of the same tree, which catches any nesting the formatter fails to parenthesize.
+func TestFilterPrecedence(t *testing.T) {
+	db, b := getDatabaseAndBuilderWithoutTable(t)
+
+	_, err := b.CreateTable(table.Named(`Props`)).
+		Columns(
+			column.Int(`ID`).PrimaryKey(),
+			column.Int(`A`),
+			column.Int(`B`),
+			column.Int(`C`),
+		).
+		Exec(db)
+	assert.NoError(t, err)
+
+	type propRow struct {
+		id      int
+		a, b, c int
+	}
+
+	var rows []propRow
+	ins := b.InsertInto(table.Named(`Props`)).Columns(`ID`, `A`, `B`, `C`)
+	for a := 0; a < 4; a++ {
+		for b := 0; b < 4; b++ {
+			for c := 0; c < 4; c++ {
+				r := propRow{id: len(rows), a: a, b: b, c: c}
+				rows = append(rows, r)
+				ins.Values(r.id, r.a, r.b, r.c)
+			}
+		}
+	}
+	_, err = ins.Exec(db)
+	assert.NoError(t, err)
+
+	columns := []struct {
+		name string
+		get  func(r propRow) int
+	}{
+		{`A`, func(r propRow) int { return r.a }},
+		{`B`, func(r propRow) int { return r.b }},
+		{`C`, func(r propRow) int { return r.c }},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+
+	var gen func(depth int) (filter.Filter, func(propRow) bool)
+	gen = func(depth int) (filter.Filter, func(propRow) bool) {
+		if depth == 0 || rng.Intn(4) == 0 {
+			col := columns[rng.Intn(len(columns))]
+			v := rng.Intn(4)
+			w := rng.Intn(4)
+			switch rng.Intn(10) {
+			case 0:
+				return filter.Equals(col.name, v), func(r propRow) bool { return col.get(r) == v }
+			case 1:
+				return filter.NotEquals(col.name, v), func(r propRow) bool { return col.get(r) != v }
+			case 2:
+				return filter.Greater(col.name, v), func(r propRow) bool { return col.get(r) > v }
+			case 3:
+				return filter.GreaterOrEqual(col.name, v), func(r propRow) bool { return col.get(r) >= v }
+			case 4:
+				return filter.Less(col.name, v), func(r propRow) bool { return col.get(r) < v }
+			case 5:
+				return filter.LessOrEqual(col.name, v), func(r propRow) bool { return col.get(r) <= v }
+			case 6:
+				return filter.In(col.name, v, w), func(r propRow) bool { return col.get(r) == v || col.get(r) == w }
+			case 7:
+				return filter.NotIn(col.name, v, w), func(r propRow) bool { return col.get(r) != v && col.get(r) != w }
+			case 8:
+				return filter.Between(col.name, v, w), func(r propRow) bool { return col.get(r) >= v && col.get(r) <= w }
+			default:
+				return filter.NotBetween(col.name, v, w), func(r propRow) bool { return col.get(r) < v || col.get(r) > w }
+			}
+		}
+
+		switch rng.Intn(4) {
+		case 0:
+			f, eval := gen(depth - 1)
+			return filter.Not(f), func(r propRow) bool { return !eval(r) }
+		case 1:
+			// Comparing a condition with a boolean nests comparisons, whose relative precedence differs
+			// between databases.
+			f, eval := gen(depth - 1)
+			v := rng.Intn(2) == 0
+			if rng.Intn(2) == 0 {
+				return filter.ExprEquals(f, filter.Value(v)), func(r propRow) bool { return eval(r) == v }
+			}
+			return filter.ExprLess(f, filter.Value(v)), func(r propRow) bool { return !eval(r) && v }
+		default:
+			all := rng.Intn(2) == 0
+			var fs []filter.Filter
+			var evals []func(propRow) bool
+			for i := 0; i < 2+rng.Intn(2); i++ {
+				f, eval := gen(depth - 1)
+				fs = append(fs, f)
+				evals = append(evals, eval)
+			}
+			if all {
+				return filter.All(fs...), func(r propRow) bool {
+					for _, eval := range evals {
+						if !eval(r) {
+							return false
+						}
+					}
+					return true
+				}
+			}
+			return filter.Any(fs...), func(r propRow) bool {
+				for _, eval := range evals {
+					if eval(r) {
+						return true
+					}
+				}
+				return false
+			}
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		f, eval := gen(4)
+
+		var exp []int
+		for _, r := range rows {
+			if eval(r) {
+				exp = append(exp, r.id)
+			}
+		}
+
+		q := b.SelectFrom(table.Named(`Props`)).
+			Columns(`ID`).
+			Where(f).
+			OrderBy(filter.OrderAsc(`ID`))
+
+		stmt, err := q.Build()
+		assert.NoError(t, err)
+
+		res, err := q.Query(db)
+		assert.NoError(t, err)
+		cleanupRows(t, res)
+
+		var act []int
+		for res.Next() {
+			var id int
+			assert.NoError(t, res.Scan(&id))
+			act = append(act, id)
+		}
+		assert.NoError(t, res.Err())
+
+		if len(exp) != len(act) {
+			t.Fatalf("query %q returned %d rows, expected %d", stmt.Stmt, len(act), len(exp))
+		}
+		for j := range exp {
+			if exp[j] != act[j] {
+				t.Fatalf("query %q returned %v, expected %v", stmt.Stmt, act, exp)
+			}
+		}
+	}
+}
+
+func TestJoins(t *testing.T) {
+	db, b := getDatabaseAndBuilderWithoutTable(t)
+
+	_, err := b.CreateTable(table.Named("TableA")).Columns(
+		column.VarChar("IDA", 32),
+		column.Int("NumA"),
+	).Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.CreateTable(table.Named("TableB")).Columns(
+		column.VarChar("IDB", 32),
+		column.Int("NumB"),
+	).Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named("TableA")).
+		Columns("IDA", "NumA").
+		Values("a", 1).
+		Values("b", 2).
+		Values("c", 3).
+		Values("d", 4).
+		Values("e", 5).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named("TableB")).
+		Columns("IDB", "NumB").
+		Values("f", 2).
+		Values("g", 3).
+		Values("h", 4).
+		Values("i", 5).
+		Values("j", 6).
+		Exec(db)
+	assert.NoError(t, err)
+
+	rows, err := b.SelectFrom(
+		table.Named("TableA").
+			As("my_table").
+			InnerJoin(table.Named("TableB")).
+			OnEqualExpressions(
+				column.Named("NumA").QualifiedBy("my_table"),
+				column.Named("NumB"),
+			),
+	).Expressions(
+		column.Named("IDA"),
+		column.Named("IDB"),
+		column.Named("NumA").QualifiedBy("my_table"),
+		column.Named("NumB"),
+	).OrderBy(
+		filter.OrderAsc("IDA"),
+	).Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	{
+		var (
+			idA  string
+			idB  string
+			numA int
+			numB int
+		)
+
+		assert.Equal(t, rows.Next(), true)
+		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
+		assert.Equal(t, idA, `b`)
+		assert.Equal(t, idB, `f`)
+		assert.Equal(t, numA, 2)
+		assert.Equal(t, numB, 2)
+
+		assert.Equal(t, rows.Next(), true)
+		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
+		assert.Equal(t, idA, `c`)
+		assert.Equal(t, idB, `g`)
+		assert.Equal(t, numA, 3)
+		assert.Equal(t, numB, 3)
+
+		assert.Equal(t, rows.Next(), true)
+		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
+		assert.Equal(t, idA, `d`)
+		assert.Equal(t, idB, `h`)
+		assert.Equal(t, numA, 4)
+		assert.Equal(t, numB, 4)
+
+		assert.Equal(t, rows.Next(), true)
+		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
+		assert.Equal(t, idA, `e`)
+		assert.Equal(t, idB, `i`)
+		assert.Equal(t, numA, 5)
+		assert.Equal(t, numB, 5)
+
+		assert.Equal(t, rows.Next(), false)
+	}
+
+	rows, err = b.SelectFrom(
 		table.Named("TableA").
 			LeftJoin(table.Named("TableB")).
 			OnEqualColumns("NumA", "NumB"),
@@ -1091,22 +2211,208 @@ func TestJoins(t *testing.T) {
 		assertNullableValueEquals(t, `g`, idB)
 		assertNullableValueEquals(t, int64(3), numB)
 
-		assert.Equal(t, rows.Next(), true)
-		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
-		assert.Equal(t, idA, `d`)
-		assert.Equal(t, numA, 4)
-		assertNullableValueEquals(t, `h`, idB)
-		assertNullableValueEquals(t, int64(4), numB)
+		assert.Equal(t, rows.Next(), true)
+		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
+		assert.Equal(t, idA, `d`)
+		assert.Equal(t, numA, 4)
+		assertNullableValueEquals(t, `h`, idB)
+		assertNullableValueEquals(t, int64(4), numB)
+
+		assert.Equal(t, rows.Next(), true)
+		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
+		assert.Equal(t, idA, `e`)
+		assert.Equal(t, numA, 5)
+		assertNullableValueEquals(t, `i`, idB)
+		assertNullableValueEquals(t, int64(5), numB)
+
+		assert.Equal(t, rows.Next(), false)
+	}
+}
+
+func TestExprFilters(t *testing.T) {
+	db, b := getDatabaseAndBuilderWithoutTable(t)
+
+	_, err := b.CreateTable(table.Named("TableA")).Columns(
+		column.VarChar("IDA", 32),
+		column.Int("NumA"),
+		column.Int("OtherA"),
+	).Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.CreateTable(table.Named("TableB")).Columns(
+		column.VarChar("IDB", 32),
+		column.Int("NumB"),
+	).Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named("TableA")).
+		Columns("IDA", "NumA", "OtherA").
+		Values("a", 1, 0).
+		Values("b", 2, 5).
+		Values("c", 3, 3).
+		Values("d", 4, 1).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.InsertInto(table.Named("TableB")).
+		Columns("IDB", "NumB").
+		Values("f", 2).
+		Values("g", 3).
+		Values("h", 4).
+		Exec(db)
+	assert.NoError(t, err)
+
+	selectIDs := func(t *testing.T, q *sel.Builder) []string {
+		t.Helper()
+
+		rows, err := q.OrderBy(filter.OrderAsc(`IDA`)).Query(db)
+		assert.NoError(t, err)
+		cleanupRows(t, rows)
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			assert.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	// Column to column
+	assert.Equal(t,
+		selectIDs(t, b.SelectFrom(table.Named("TableA")).
+			Columns("IDA").
+			Where(filter.ExprGreater(column.Named("NumA"), column.Named("OtherA")))),
+		[]string{`a`, `d`},
+	)
+
+	// Qualified columns in a join, compared with each other and with values
+	assert.Equal(t,
+		selectIDs(t, b.SelectFrom(
+			table.Named("TableA").
+				As("a").
+				InnerJoin(table.Named("TableB").As("b")).
+				OnEqualExpressions(
+					column.Named("NumA").QualifiedBy("a"),
+					column.Named("NumB").QualifiedBy("b"),
+				),
+		).
+			Expressions(column.Named("IDA").QualifiedBy("a")).
+			WhereAll(
+				filter.ExprLessOrEqual(column.Named("OtherA").QualifiedBy("a"), column.Named("NumB").QualifiedBy("b")),
+				filter.ExprNotEquals(column.Named("IDB").QualifiedBy("b"), filter.Value("h")),
+			)),
+		[]string{`c`},
+	)
+
+	// Subqueries
+	assert.Equal(t,
+		selectIDs(t, b.SelectFrom(table.Named("TableA")).
+			Columns("IDA").
+			Where(filter.ExprIn(
+				column.Named("NumA"),
+				b.SelectFrom(table.Named("TableB")).Columns("NumB").Where(filter.Greater("NumB", 2)),
+			))),
+		[]string{`c`, `d`},
+	)
+	assert.Equal(t,
+		selectIDs(t, b.SelectFrom(table.Named("TableA")).
+			Columns("IDA").
+			WhereAll(
+				filter.ExprNotIn(column.Named("NumA"), b.SelectFrom(table.Named("TableB")).Columns("NumB")),
+				filter.Less("NumA", 10),
+			)),
+		[]string{`a`},
+	)
+	assert.Equal(t,
+		selectIDs(t, b.SelectFrom(table.Named("TableA")).
+			Columns("IDA").
+			Where(filter.ExprEquals(
+				column.Named("NumA"),
+				b.SelectFrom(table.Named("TableB")).Expressions(functions.CountAll()).Where(filter.Less("NumB", 4)),
+			))),
+		[]string{`b`},
+	)
+}
+
+func TestColumnRefs(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	var (
+		id   = column.NewRef[string](`ID`)
+		num  = column.NewRef[int](`NumberField`)
+		text = column.NewRef[string](`TextField`)
+	)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(column.Names(id, num, text)...).
+		Values(`a`, 1, `x`).
+		Values(`b`, 2, nil).
+		Values(`c`, 3, `z`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	_, err = b.Update(table.Named(`Example`)).
+		Set(text.To(`y`)).
+		Where(id.Eq(`b`)).
+		Exec(db)
+	assert.NoError(t, err)
 
-		assert.Equal(t, rows.Next(), true)
-		assert.NoError(t, rows.Scan(&idA, &idB, &numA, &numB))
-		assert.Equal(t, idA, `e`)
-		assert.Equal(t, numA, 5)
-		assertNullableValueEquals(t, `i`, idB)
-		assertNullableValueEquals(t, int64(5), numB)
+	selectIDs := func(t *testing.T, f filter.Filter, o filter.Order) []string {
+		t.Helper()
 
-		assert.Equal(t, rows.Next(), false)
+		rows, err := b.SelectFrom(table.Named(`Example`).As(`e`)).
+			Expressions(id.QualifiedBy(`e`).As(`the_id`)).
+			Where(f).
+			OrderBy(o).
+			Query(db)
+		assert.NoError(t, err)
+		cleanupRows(t, rows)
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			assert.NoError(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+		return ids
 	}
+
+	n := num.QualifiedBy(`e`)
+	assert.Equal(t, selectIDs(t, n.Gt(1), n.Asc()), []string{`b`, `c`})
+	assert.Equal(t, selectIDs(t, n.Ge(1), n.Desc()), []string{`c`, `b`, `a`})
+	assert.Equal(t, selectIDs(t, n.Lt(3), n.Asc()), []string{`a`, `b`})
+	assert.Equal(t, selectIDs(t, n.Le(1), n.Asc()), []string{`a`})
+	assert.Equal(t, selectIDs(t, n.Ne(2), n.Asc()), []string{`a`, `c`})
+	assert.Equal(t, selectIDs(t, n.In(1, 3), n.Asc()), []string{`a`, `c`})
+	assert.Equal(t, selectIDs(t, n.NotIn(1, 3), n.Asc()), []string{`b`})
+	assert.Equal(t, selectIDs(t, n.In(), n.Asc()), nil)
+	assert.Equal(t, selectIDs(t, n.Between(2, 3), n.Asc()), []string{`b`, `c`})
+	// Like the other Expr filters, ExprBetween's bounds are expressions.
+	assert.Equal(t, selectIDs(t, filter.ExprBetween(n, filter.Value(2), n), n.Asc()), []string{`b`, `c`})
+	assert.Equal(t, selectIDs(t, filter.ExprNotBetween(n, filter.Value(2), filter.Value(3)), n.Asc()), []string{`a`})
+	assert.Equal(t, selectIDs(t, text.Eq(`y`), id.Asc()), []string{`b`})
+	assert.Equal(t, selectIDs(t, text.IsNull(), id.Asc()), nil)
+	assert.Equal(t, selectIDs(t, text.IsNotNull(), id.Asc()), []string{`a`, `b`, `c`})
+
+	rows, err := b.SelectFrom(
+		table.Named(`Example`).
+			As(`e1`).
+			InnerJoin(table.Named(`Example`).As(`e2`)).
+			On(num.QualifiedBy(`e1`).EqRef(num.QualifiedBy(`e2`)).IntoExpr()),
+	).
+		Expressions(id.QualifiedBy(`e1`), id.QualifiedBy(`e2`)).
+		Where(id.QualifiedBy(`e1`).Eq(`c`)).
+		Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	assert.Equal(t, rows.Next(), true)
+	var id1, id2 string
+	assert.NoError(t, rows.Scan(&id1, &id2))
+	assert.Equal(t, id1, `c`)
+	assert.Equal(t, id2, `c`)
+	assert.Equal(t, rows.Next(), false)
 }
 
 func TestMultipleJoins(t *testing.T) {
@@ -1225,3 +2531,596 @@ func assertNull(
 		t.Fatalf("expected nil, got %v", got)
 	}
 }
+
+func TestInterpolate(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	var d statement.Dialect = formatter.Sqlite{}
+	if isMySQL() {
+		d = formatter.Mysql{}
+	}
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `it's a "quote"`).
+		Values(`b`, 2, `back\slash`).
+		Values(`c`, nil, `?`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	stmt, err := b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		Where(filter.Any(
+			filter.In(`TextField`, `it's a "quote"`, `back\slash`),
+			filter.All(filter.IsNull(`NumberField`), filter.Equals(`TextField`, `?`)),
+		)).
+		OrderBy(filter.OrderAsc(`ID`)).
+		Build()
+	assert.NoError(t, err)
+
+	// Interpolated statements aren't meant to be executed, but running this one checks that every
+	// literal came out the way the database reads it.
+	inlined, err := stmt.Interpolate(d)
+	assert.NoError(t, err)
+
+	rows, err := db.Query(inlined)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		assert.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	assert.Equal(t, ids, []string{`a`, `b`, `c`})
+}
+
+func TestPrettyFormatting(t *testing.T) {
+	db, _ := getDatabaseAndBuilder(t)
+
+	f := sqlbuilder.Formatter(formatter.Sqlite{})
+	if isMySQL() {
+		f = formatter.Mysql{}
+	}
+	pretty := sqlbuilder.New(formatter.Pretty{Formatter: f, Width: 20})
+
+	_, err := pretty.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `x AND (y`).
+		Values(`b`, 2, `x`).
+		Values(`c`, 3, `y`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	sel := pretty.SelectFrom(
+		table.Named(`Example`).
+			As(`e1`).
+			InnerJoin(table.Named(`Example`).As(`e2`)).
+			On(expr.Raw(`e1.NumberField = e2.NumberField - ?`, 1).IntoExpr()),
+	).
+		Expressions(
+			column.Named(`ID`).QualifiedBy(`e1`),
+			expr.Raw(`e2.NumberField * ?`, 10).As(`scaled`),
+		).
+		Where(filter.Any(
+			filter.Equals(`e1.TextField`, `x AND (y`),
+			filter.All(
+				filter.GreaterOrEqual(`e2.NumberField`, 3),
+				filter.NotEquals(`e2.TextField`, `z`),
+			),
+		)).
+		OrderBy(filter.OrderAsc(`e1.ID`))
+
+	stmt, err := sel.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Count(stmt.Stmt, "\n") > 5, true)
+
+	rows, err := sel.Query(db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	type result struct {
+		ID     string
+		Scaled int
+	}
+	var results []result
+	for rows.Next() {
+		var r result
+		assert.NoError(t, rows.Scan(&r.ID, &r.Scaled))
+		results = append(results, r)
+	}
+	assert.Equal(t, results, []result{{`a`, 20}, {`b`, 30}})
+}
+
+func TestParseLegacyMysql(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `foo`).
+		Values(`b`, 2, `bar`).
+		Values(`c`, 3, `baz`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	n, err := parser.Mysql{}.Parse(
+		"select ID, concat(TextField, ?) as txt\n"+
+			"from `Example`\n"+
+			"where NumberField >= ? and char_length(TextField) = 3 # legacy comment\n"+
+			"order by ID desc limit 1, 5",
+		`!`, 2,
+	)
+	assert.NoError(t, err)
+
+	f := sqlbuilder.Formatter(formatter.Sqlite{})
+	if isMySQL() {
+		f = formatter.Mysql{}
+	}
+	sb := &strings.Builder{}
+	assert.NoError(t, f.FormatNode(sb, n))
+
+	rows, err := db.Query(sb.String(), `!`, 2)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	var ids, txts []string
+	for rows.Next() {
+		var id, txt string
+		assert.NoError(t, rows.Scan(&id, &txt))
+		ids = append(ids, id)
+		txts = append(txts, txt)
+	}
+	assert.Equal(t, ids, []string{`b`})
+	assert.Equal(t, txts, []string{`bar!`})
+}
+
+func TestFingerprint(t *testing.T) {
+	_, b := getDatabaseAndBuilder(t)
+
+	stmts, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `aa`).
+		Values(`b`, 2, `bb`).
+		Values(`c`, 3, `cc`).
+		BuildBatchesOfSize(2)
+	assert.NoError(t, err)
+	assert.Equal(t, len(stmts), 2)
+	assert.Equal(t, stmts[0].Fingerprint(), stmts[1].Fingerprint())
+
+	selectIn := func(ids ...any) statement.Statement {
+		stmt, err := b.SelectFrom(table.Named(`Example`)).
+			Columns(`ID`).
+			Where(filter.In(`ID`, ids...)).
+			Build()
+		assert.NoError(t, err)
+		return stmt
+	}
+	fp := selectIn(`a`).Fingerprint()
+	assert.Equal(t, selectIn(`a`, `b`, `c`).Fingerprint(), fp)
+	assert.Equal(t, strings.Contains(fp.Normalized, `IN (?+)`), true)
+}
+
+func TestBuildErrors(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.Update(table.Named(`Example`)).
+		Where(filter.Equals(`ID`, `a`)).
+		Exec(db)
+
+	var be *sqlbuilder.BuildError
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `SET`)
+
+	_, err = b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`).
+		Values(`a`).
+		Exec(db)
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `VALUES`)
+
+	_, err = b.SelectFrom(table.Named(`Example`)).
+		Expressions(expr.Case().Else(1)).
+		Query(db)
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `CASE`)
+
+	// DDL can't bind arguments, so placeholders in defaults and generated columns are rejected.
+	_, err = b.CreateTable(table.Named(`Defaults`)).
+		Columns(
+			column.BigInt(`A`).DefaultExpr(filter.Value(5)),
+		).
+		Build()
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `DEFAULT`)
+
+	_, err = b.CreateTable(table.Named(`Defaults`)).
+		Columns(
+			column.BigInt(`B`).GeneratedAs(filter.Value(6), true),
+		).
+		Build()
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `GENERATED`)
+
+	// Engines, character sets and collations are written unquoted, so they have to be plain names.
+	_, err = b.CreateTable(table.Named(`Options`)).
+		Columns(column.VarChar(`A`, 32).Collate(`utf8mb4_bin) DEFAULT ('x'`)).
+		Build()
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `COLLATE`)
+
+	_, err = b.CreateTable(table.Named(`Options`)).
+		Columns(column.VarChar(`A`, 32)).
+		DefaultCharset(`utf8mb4 COMMENT='x'`).
+		Build()
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `CREATE TABLE`)
+}
+
+func TestUnboundedWrites(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `aa`).
+		Values(`b`, 2, `bb`).
+		Exec(db)
+	assert.NoError(t, err)
+
+	var be *sqlbuilder.BuildError
+
+	_, err = b.Update(table.Named(`Example`)).
+		SetFieldTo(`NumberField`, 3).
+		Exec(db)
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `WHERE`)
+
+	// A filter which matches everything, like an empty search, is no better than no filter.
+	_, err = b.DeleteFrom(table.Named(`Example`)).
+		Where(filter.All()).
+		Exec(db)
+	assert.Equal(t, errors.As(err, &be), true)
+	assert.Equal(t, be.Clause, `WHERE`)
+
+	res, err := b.Update(table.Named(`Example`)).
+		SetFieldTo(`NumberField`, 3).
+		All().
+		Exec(db)
+	assert.NoError(t, err)
+	n, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, n, int64(2))
+
+	res, err = b.SetAllowUnboundedWrites(true).
+		DeleteFrom(table.Named(`Example`)).
+		Exec(db)
+	assert.NoError(t, err)
+	n, err = res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, n, int64(2))
+}
+
+func TestInTx(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+	ctx := context.Background()
+
+	insert := func(tx *sqlbuilder.Tx, id string) error {
+		_, err := b.InsertInto(table.Named(`Example`)).
+			Columns(`ID`, `NumberField`, `TextField`).
+			Values(id, 1, id).
+			ExecContext(ctx, tx)
+		return err
+	}
+	ids := func() []string {
+		rows, err := b.SelectFrom(table.Named(`Example`)).
+			Columns(`ID`).
+			OrderBy(filter.OrderAsc(`ID`)).
+			Query(db)
+		assert.NoError(t, err)
+		cleanupRows(t, rows)
+
+		var res []string
+		for rows.Next() {
+			var id string
+			assert.NoError(t, rows.Scan(&id))
+			res = append(res, id)
+		}
+		return res
+	}
+
+	errFailed := errors.New(`failed`)
+	err := b.InTx(ctx, db, func(tx *sqlbuilder.Tx) error {
+		assert.NoError(t, insert(tx, `x`))
+		return errFailed
+	})
+	assert.Equal(t, errors.Is(err, errFailed), true)
+	assert.Equal(t, len(ids()), 0)
+
+	attempts := 0
+	err = b.InTx(ctx, db, func(tx *sqlbuilder.Tx) error {
+		attempts++
+		assert.NoError(t, insert(tx, `a`))
+		if attempts == 1 {
+			return errors.New(`Error 1213 (40001): Deadlock found when trying to get lock`)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, attempts, 2)
+	assert.Equal(t, ids(), []string{`a`})
+
+	err = b.InTx(ctx, db, func(tx *sqlbuilder.Tx) error {
+		assert.NoError(t, insert(tx, `b`))
+
+		err := tx.InTx(ctx, func(tx *sqlbuilder.Tx) error {
+			assert.NoError(t, insert(tx, `c`))
+			return tx.InTx(ctx, func(tx *sqlbuilder.Tx) error {
+				assert.NoError(t, insert(tx, `d`))
+				return errFailed
+			})
+		})
+		assert.Equal(t, errors.Is(err, errFailed), true)
+
+		return tx.InTx(ctx, func(tx *sqlbuilder.Tx) error {
+			return insert(tx, `e`)
+		})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ids(), []string{`a`, `b`, `e`})
+
+	b.SetRetryPolicy(sqlbuilder.RetryPolicy{MaxAttempts: 1})
+	attempts = 0
+	err = b.InTx(ctx, db, func(tx *sqlbuilder.Tx) error {
+		attempts++
+		return errors.New(`database is locked`)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, attempts, 1)
+}
+
+func TestInTxRetriesLockedDatabase(t *testing.T) {
+	if isMySQL() {
+		t.Skip(`MySQL waits for locks instead of failing straight away`)
+	}
+
+	db, b := getDatabaseAndBuilder(t)
+	ctx := context.Background()
+
+	insert := func(tx *sql.Tx, id string) error {
+		_, err := b.InsertInto(table.Named(`Example`)).
+			Columns(`ID`, `NumberField`, `TextField`).
+			Values(id, 1, id).
+			ExecContext(ctx, tx)
+		return err
+	}
+
+	// Hold the write lock on a second connection until the first attempt has failed.
+	conn, err := db.Conn(ctx)
+	assert.NoError(t, err)
+	defer conn.Close()
+	holder, err := conn.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, insert(holder, `held`))
+
+	// Don't wait for the lock: fail with SQLITE_BUSY straight away.
+	waiter, err := db.Conn(ctx)
+	assert.NoError(t, err)
+	defer waiter.Close()
+	_, err = waiter.ExecContext(ctx, `PRAGMA busy_timeout = 0`)
+	assert.NoError(t, err)
+
+	b.SetRetryPolicy(sqlbuilder.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff: func(retry int) time.Duration {
+			if retry == 1 {
+				assert.NoError(t, holder.Commit())
+			}
+			return 0
+		},
+	})
+
+	var errs []error
+	err = b.InTx(ctx, waiter, func(tx *sqlbuilder.Tx) error {
+		err := insert(tx.Tx, `a`)
+		errs = append(errs, err)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(errs), 2)
+	assert.Equal(t, sqlbuilder.IsRetryable(errs[0]), true)
+	assert.NoError(t, errs[1])
+
+	var n int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM Example`).Scan(&n))
+	assert.Equal(t, n, 2)
+}
+
+func TestHooks(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	var events []sqlbuilder.Event
+	logs := &strings.Builder{}
+	slowLogs := &strings.Builder{}
+	counter := &hooks.Counter{}
+	b.AddHooks(
+		func(ctx context.Context, e *sqlbuilder.Event, next func(context.Context) error) error {
+			err := next(ctx)
+			events = append(events, *e)
+			return err
+		},
+		hooks.Slog(slog.New(slog.NewTextHandler(logs, nil)), slog.LevelInfo),
+		hooks.Slow(time.Hour, hooks.Slog(slog.New(slog.NewTextHandler(slowLogs, nil)), slog.LevelWarn)),
+		counter.Hook(),
+	)
+
+	for _, id := range []string{`a`, `b`} {
+		_, err := b.InsertInto(table.Named(`Example`)).
+			Columns(`ID`, `NumberField`, `TextField`).
+			Values(id, 1, id).
+			Values(id+id, 2, id).
+			Exec(db)
+		assert.NoError(t, err)
+	}
+
+	rows, err := b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		Where(filter.Equals(`NumberField`, 1)).
+		QueryContext(context.Background(), db)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+
+	_, err = b.SelectFrom(table.Named(`Missing`)).
+		Columns(`ID`).
+		QueryRow(db)
+	assert.NoError(t, err)
+
+	assert.Equal(t, len(events), 4)
+	assert.Equal(t, events[0].Method, `Exec`)
+	assert.Equal(t, events[0].RowsAffected, int64(2))
+	assert.Equal(t, events[0].Args, []any{`a`, 1, `a`, `aa`, 2, `a`})
+	assert.Equal(t, events[2].Method, `Query`)
+	assert.Equal(t, events[2].RowsAffected, int64(-1))
+	assert.Equal(t, events[3].Method, `QueryRow`)
+	assert.Error(t, events[3].Err)
+
+	assert.Equal(t, strings.Count(logs.String(), "\n"), 4)
+	assert.Equal(t, strings.Count(logs.String(), `level=ERROR`), 1)
+	assert.Equal(t, slowLogs.String(), ``)
+
+	stats := counter.Stats()
+	assert.Equal(t, len(stats), 3)
+	for _, s := range stats {
+		if strings.HasPrefix(s.Query, `INSERT`) {
+			assert.Equal(t, s.Count, int64(2))
+			assert.Equal(t, s.RowsAffected, int64(4))
+		}
+		if strings.Contains(s.Query, `Missing`) {
+			assert.Equal(t, s.Errors, int64(1))
+		}
+	}
+}
+
+func TestHookMiddleware(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	// The context a hook passes on reaches the driver, even through the methods which don't take one.
+	_, err := b.InsertInto(table.Named(`Example`)).
+		Columns(`ID`, `NumberField`, `TextField`).
+		Values(`a`, 1, `a`).
+		WithHooks(func(ctx context.Context, e *sqlbuilder.Event, next func(context.Context) error) error {
+			ctx, cancel := context.WithCancel(ctx)
+			cancel()
+			return next(ctx)
+		}).
+		Exec(db)
+	assert.Equal(t, errors.Is(err, context.Canceled), true)
+
+	// Hooks run in the order they were added, the first one outermost, and can replace the error.
+	var order []string
+	hook := func(name string) sqlbuilder.Hook {
+		return func(ctx context.Context, e *sqlbuilder.Event, next func(context.Context) error) error {
+			order = append(order, name)
+			err := next(ctx)
+			order = append(order, name)
+			return err
+		}
+	}
+	refused := errors.New(`refused`)
+	_, err = b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		WithHooks(hook(`outer`), hook(`inner`)).
+		WithHooks(func(ctx context.Context, e *sqlbuilder.Event, next func(context.Context) error) error {
+			return refused
+		}).
+		Query(db)
+	assert.Equal(t, errors.Is(err, refused), true)
+	assert.Equal(t, order, []string{`outer`, `inner`, `inner`, `outer`})
+
+	// A hook can't skip the statement without saying why.
+	_, err = b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		WithHooks(func(ctx context.Context, e *sqlbuilder.Event, next func(context.Context) error) error {
+			return nil
+		}).
+		QueryRow(db)
+	assert.Error(t, err)
+
+	var n int
+	row, err := b.SelectFrom(table.Named(`Example`)).
+		Expressions(functions.CountAll()).
+		WithHooks(hook(`only`)).
+		QueryRowContext(context.Background(), db)
+	assert.NoError(t, err)
+	assert.NoError(t, row.Scan(&n))
+	assert.Equal(t, n, 0)
+}
+
+func TestStatementCache(t *testing.T) {
+	db, b := getDatabaseAndBuilder(t)
+
+	cache := stmtcache.New(db, 2)
+	t.Cleanup(func() { assert.NoError(t, cache.Close()) })
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := b.InsertInto(table.Named(`Example`)).
+				Columns(`ID`, `NumberField`, `TextField`).
+				Values(fmt.Sprint(i), i, `x`).
+				Exec(cache)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, cache.Len(), 1)
+
+	count := func(f filter.Filter) int {
+		var n int
+		row, err := b.SelectFrom(table.Named(`Example`)).
+			Expressions(functions.CountAll()).
+			Where(f).
+			QueryRow(cache)
+		assert.NoError(t, err)
+		assert.NoError(t, row.Scan(&n))
+		return n
+	}
+	assert.Equal(t, count(filter.Less(`NumberField`, 5)), 5)
+	assert.Equal(t, count(filter.Less(`NumberField`, 10)), 10)
+	assert.Equal(t, cache.Len(), 2)
+
+	// A third statement evicts the least recently used one.
+	assert.Equal(t, count(filter.Greater(`NumberField`, 15)), 4)
+	assert.Equal(t, cache.Len(), 2)
+
+	rows, err := b.SelectFrom(table.Named(`Example`)).
+		Columns(`ID`).
+		Where(filter.Equals(`NumberField`, 3)).
+		Query(cache)
+	assert.NoError(t, err)
+	cleanupRows(t, rows)
+	assert.Equal(t, rows.Next(), true)
+
+	// Transactions run the cache's statements, and can't use them once they're done.
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	txView := cache.Tx(tx)
+	_, err = b.DeleteFrom(table.Named(`Example`)).All().Exec(txView)
+	assert.NoError(t, err)
+	assert.Equal(t, cache.Len(), 2)
+
+	row, err := b.SelectFrom(table.Named(`Example`)).
+		Expressions(functions.CountAll()).
+		QueryRow(txView)
+	assert.NoError(t, err)
+	var n int
+	assert.NoError(t, row.Scan(&n))
+	assert.Equal(t, n, 0)
+	assert.NoError(t, tx.Rollback())
+
+	_, err = b.DeleteFrom(table.Named(`Example`)).All().Exec(txView)
+	assert.Equal(t, errors.Is(err, sql.ErrTxDone), true)
+	assert.Equal(t, cache.Len(), 2)
+	assert.Equal(t, count(filter.All()), 20)
+}
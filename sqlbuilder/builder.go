@@ -6,18 +6,35 @@ import (
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/delete"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/insert"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/dispatch"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/sel"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/table"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/update"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/view"
 )
 
+// BuildError is the error returned when a statement can't be built, because it's invalid or because
+// the formatter's dialect can't express it. Use errors.As to find out which clause it's about.
+type BuildError = ast.BuildError
+
+// Hook wraps each statement a builder runs, e.g. to log or trace it; see the hooks package for some
+// ready-made ones.
+type Hook = dispatch.Hook
+
+// Event describes a statement being run, for hooks.
+type Event = dispatch.Event
+
 type Formatter interface {
-	FormatNode(w io.Writer, n ast.Node)
+	FormatNode(w io.Writer, n ast.Node) error
 }
 
 type Builder struct {
 	f        Formatter
 	database string
+
+	allowUnboundedWrites bool
+	retryPolicy          RetryPolicy
+	hooks                []Hook
 }
 
 func New(f Formatter) *Builder {
@@ -31,28 +48,54 @@ func (b *Builder) SetDatabase(db string) *Builder {
 	return b
 }
 
+// SetAllowUnboundedWrites controls whether updates and deletes without a WHERE condition are built.
+// By default they fail, and each one has to opt in with All.
+func (b *Builder) SetAllowUnboundedWrites(allow bool) *Builder {
+	b.allowUnboundedWrites = allow
+	return b
+}
+
+// AddHooks adds hooks to every builder created from now on. The first hook added is the outermost, so
+// it runs first and sees the statement finish last.
+func (b *Builder) AddHooks(hs ...Hook) *Builder {
+	b.hooks = append(b.hooks, hs...)
+	return b
+}
+
 func (b *Builder) qualifiedTableExpr(expr ast.IntoTableExpr) ast.IntoTableExpr {
 	if b.database == `` {
 		return expr
 	}
+	if bt, ok := expr.(*table.BareTable); ok {
+		// Keep the table's schema around for the builders that use it.
+		return bt.Qualified(b.database)
+	}
 	qualified := ast.QualifyTableExpr(expr.IntoTableExpr(), b.database)
 	return qualified
 }
 
 func (b *Builder) SelectFrom(tableExpr ast.IntoTableExpr) *sel.Builder {
-	return sel.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr))
+	return sel.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr)).WithHooks(b.hooks...)
 }
 
 func (b *Builder) DeleteFrom(tableExpr ast.IntoTableExpr) *delete.Builder {
-	return delete.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr))
+	d := delete.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr)).WithHooks(b.hooks...)
+	if b.allowUnboundedWrites {
+		d.All()
+	}
+	return d
 }
 
 func (b *Builder) Update(tableExpr ast.IntoTableExpr) *update.Builder {
-	return update.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr))
+	u := update.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr)).WithHooks(b.hooks...)
+	if b.allowUnboundedWrites {
+		u.All()
+	}
+	return u
 }
 
 func (b *Builder) InsertInto(tableExpr ast.IntoTableExpr) *insert.Builder {
-	return insert.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr))
+	return insert.NewBuilder(b.f, b.qualifiedTableExpr(tableExpr)).WithHooks(b.hooks...)
 }
 
 // CreateTable starts a CREATE TABLE for the given table. It accepts only a bare table
@@ -60,5 +103,20 @@ func (b *Builder) InsertInto(tableExpr ast.IntoTableExpr) *insert.Builder {
 func (b *Builder) CreateTable(ref table.BareTableRef) *table.CreateBuilder {
 	qualified := b.qualifiedTableExpr(ref)
 	name := ast.BaseTableName(qualified.IntoTableExpr())
-	return table.NewCreateBuilder(b.f, name)
+	return table.NewCreateBuilder(b.f, name).WithHooks(b.hooks...)
+}
+
+// CreateView starts a CREATE VIEW for the given view. Like CreateTable, it accepts only a bare
+// reference (the result of table.Named("foo")); the same reference can be used to select from the view.
+func (b *Builder) CreateView(ref table.BareTableRef) *view.CreateBuilder {
+	qualified := b.qualifiedTableExpr(ref)
+	name := ast.BaseTableName(qualified.IntoTableExpr())
+	return view.NewCreateBuilder(b.f, name).WithHooks(b.hooks...)
+}
+
+// DropView starts a DROP VIEW for the given view.
+func (b *Builder) DropView(ref table.BareTableRef) *view.DropBuilder {
+	qualified := b.qualifiedTableExpr(ref)
+	name := ast.BaseTableName(qualified.IntoTableExpr())
+	return view.NewDropBuilder(b.f, name).WithHooks(b.hooks...)
 }
@@ -9,9 +9,15 @@ type columnTyper interface {
 type baseColumnBuilder[T any, U columnTyper] struct {
 	name        string
 	defaultVal  *T
+	defaultExpr ast.IntoExpr
 	defaultNull bool
 	nullable    *bool
 	primaryKey  bool
+	collation   string
+	comment     string
+
+	generatedExpr   ast.IntoExpr
+	generatedStored bool
 
 	parent U
 }
@@ -25,12 +31,26 @@ func newBaseColumnBuilder[T any, U columnTyper](name string, parent U) *baseColu
 
 func (b *baseColumnBuilder[T, U]) Default(val T) U {
 	b.defaultVal = &val
+	b.defaultExpr = nil
+	b.defaultNull = false
+	return b.parent
+}
+
+// DefaultExpr sets the column's default to an arbitrary expression, such as
+// functions.CurrentTimestamp(). Anything other than a literal is wrapped in parentheses, which both
+// MySQL (8.0.13+) and SQLite require for expression defaults. DDL can't bind arguments, so building a
+// table fails if expr contains a placeholder, e.g. one made with filter.Value.
+func (b *baseColumnBuilder[T, U]) DefaultExpr(expr ast.IntoExpr) U {
+	b.defaultExpr = expr
+	b.defaultVal = nil
+	b.defaultNull = false
 	return b.parent
 }
 
 func (b *baseColumnBuilder[T, U]) DefaultNull() U {
 	b.defaultNull = true
 	b.defaultVal = nil
+	b.defaultExpr = nil
 	return b.parent
 }
 
@@ -51,6 +71,28 @@ func (b *baseColumnBuilder[T, U]) PrimaryKey() U {
 	return b.parent
 }
 
+// Collate sets the column's collation, e.g. utf8mb4_bin in MySQL or NOCASE in SQLite.
+func (b *baseColumnBuilder[T, U]) Collate(collation string) U {
+	b.collation = collation
+	return b.parent
+}
+
+// Comment attaches a comment to the column. Only MySQL supports column comments; building a CREATE
+// TABLE with one for SQLite fails.
+func (b *baseColumnBuilder[T, U]) Comment(comment string) U {
+	b.comment = comment
+	return b.parent
+}
+
+// GeneratedAs makes this a generated (computed) column whose value is always the result of expr. A
+// stored column is computed when the row is written, a virtual one when it is read. Requires MySQL
+// 5.7+ or SQLite 3.31+. Like DefaultExpr, expr can't contain placeholders.
+func (b *baseColumnBuilder[T, U]) GeneratedAs(expr ast.IntoExpr, stored bool) U {
+	b.generatedExpr = expr
+	b.generatedStored = stored
+	return b.parent
+}
+
 func (b *baseColumnBuilder[T, U]) Build() *ast.ColumnSpec {
 	cs := ast.NewColumnSpec(b.name, b.parent.columnType()).
 		WithNullabilityFromBool(b.nullable)
@@ -58,7 +100,15 @@ func (b *baseColumnBuilder[T, U]) Build() *ast.ColumnSpec {
 	if b.defaultNull {
 		cs.WithDefault(ast.NewNullLiteral())
 	}
+	if b.defaultExpr != nil {
+		cs.WithDefault(b.defaultExpr)
+	}
+	if b.generatedExpr != nil {
+		cs.WithGenerated(b.generatedExpr, b.generatedStored)
+	}
 	cs.SetPrimaryKey(b.primaryKey)
+	cs.WithCollation(b.collation)
+	cs.WithComment(b.comment)
 
 	return cs
 }
@@ -2,12 +2,25 @@ package column
 
 import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
 
+// Builder is implemented by every column definition (e.g. column.Int("id")). It's useful for
+// declaring a table's columns once and sharing them between CreateTable and table.Named(...).WithColumns.
+type Builder interface {
+	Build() *ast.ColumnSpec
+}
+
 type ColumnExpressionBuilder struct {
-	ident *ast.Identifier
+	ident     *ast.Identifier
+	qualifier string
 }
 
 func (b *ColumnExpressionBuilder) IntoExpr() ast.Expr {
-	return b.ident.IntoExpr()
+	if b.qualifier == `` {
+		return b.ident.IntoExpr()
+	}
+	return &ast.Selector{
+		SelectFrom: ast.NewIdentifier(b.qualifier),
+		FieldName:  b.ident,
+	}
 }
 
 func Named(name string) *ColumnExpressionBuilder {
@@ -16,9 +29,13 @@ func Named(name string) *ColumnExpressionBuilder {
 	}
 }
 
-func (b *ColumnExpressionBuilder) QualifiedBy(qualifier string) ast.IntoExpr {
-	return &ast.Selector{
-		SelectFrom: ast.NewIdentifier(qualifier),
-		FieldName:  b.ident,
+func (b *ColumnExpressionBuilder) QualifiedBy(qualifier string) *ColumnExpressionBuilder {
+	return &ColumnExpressionBuilder{
+		ident:     b.ident,
+		qualifier: qualifier,
 	}
 }
+
+func (b *ColumnExpressionBuilder) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(b, alias)
+}
@@ -1,10 +1,6 @@
 package column
 
-import (
-	"time"
-
-	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
-)
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
 
 type tinyIntColumnBuilder struct {
 	*integerColumnBuilder[int8, *tinyIntColumnBuilder]
@@ -161,7 +157,7 @@ func LongBlob(name string) *longBlobColumnBuilder {
 }
 
 type dateTimeColumnBuilder struct {
-	*baseColumnBuilder[time.Time, *dateTimeColumnBuilder]
+	*timeColumnBuilder[*dateTimeColumnBuilder]
 }
 
 func (b *dateTimeColumnBuilder) columnType() ast.ColumnType {
@@ -170,6 +166,20 @@ func (b *dateTimeColumnBuilder) columnType() ast.ColumnType {
 
 func DateTime(name string) *dateTimeColumnBuilder {
 	b := &dateTimeColumnBuilder{}
-	b.baseColumnBuilder = newBaseColumnBuilder[time.Time](name, b)
+	b.timeColumnBuilder = newTimeColumnBuilder(name, b)
+	return b
+}
+
+type timestampColumnBuilder struct {
+	*timeColumnBuilder[*timestampColumnBuilder]
+}
+
+func (b *timestampColumnBuilder) columnType() ast.ColumnType {
+	return ast.Timestamp()
+}
+
+func Timestamp(name string) *timestampColumnBuilder {
+	b := &timestampColumnBuilder{}
+	b.timeColumnBuilder = newTimeColumnBuilder(name, b)
 	return b
 }
@@ -0,0 +1,137 @@
+package column
+
+import (
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/filter"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+// Ref is a typed reference to a column, optionally qualified by a table name or alias. T is the Go type
+// of the column's values, so the filters made from a Ref only accept values of that type. Refs are meant
+// to be declared once per table and shared, e.g.
+//
+//	var (
+//		UserID   = column.NewRef[int64]("id")
+//		UserName = column.NewRef[string]("name")
+//	)
+type Ref[T any] struct {
+	name      string
+	qualifier string
+}
+
+func NewRef[T any](name string) Ref[T] {
+	return Ref[T]{
+		name: name,
+	}
+}
+
+// QualifiedBy returns a copy of r qualified by the given table name or alias.
+func (r Ref[T]) QualifiedBy(qualifier string) Ref[T] {
+	r.qualifier = qualifier
+	return r
+}
+
+// Name returns the column's unqualified name.
+func (r Ref[T]) Name() string {
+	return r.name
+}
+
+func (r Ref[T]) IntoExpr() ast.Expr {
+	if r.qualifier == `` {
+		return ast.NewIdentifier(r.name)
+	}
+	return &ast.Selector{
+		SelectFrom: ast.NewIdentifier(r.qualifier),
+		FieldName:  ast.NewIdentifier(r.name),
+	}
+}
+
+// As aliases the column, for use in a select list.
+func (r Ref[T]) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(r, alias)
+}
+
+func (r Ref[T]) Eq(val T) filter.Filter {
+	return filter.ExprEquals(r, filter.Value(val))
+}
+
+func (r Ref[T]) Ne(val T) filter.Filter {
+	return filter.ExprNotEquals(r, filter.Value(val))
+}
+
+func (r Ref[T]) Gt(val T) filter.Filter {
+	return filter.ExprGreater(r, filter.Value(val))
+}
+
+func (r Ref[T]) Ge(val T) filter.Filter {
+	return filter.ExprGreaterOrEqual(r, filter.Value(val))
+}
+
+func (r Ref[T]) Lt(val T) filter.Filter {
+	return filter.ExprLess(r, filter.Value(val))
+}
+
+func (r Ref[T]) Le(val T) filter.Filter {
+	return filter.ExprLessOrEqual(r, filter.Value(val))
+}
+
+func (r Ref[T]) In(vals ...T) filter.Filter {
+	return filter.ExprInValues(r, vals...)
+}
+
+func (r Ref[T]) NotIn(vals ...T) filter.Filter {
+	return filter.ExprNotInValues(r, vals...)
+}
+
+func (r Ref[T]) Between(low, high T) filter.Filter {
+	return filter.ExprBetweenValues(r, low, high)
+}
+
+func (r Ref[T]) IsNull() filter.Filter {
+	return filter.ExprIsNull(r)
+}
+
+func (r Ref[T]) IsNotNull() filter.Filter {
+	return filter.ExprIsNotNull(r)
+}
+
+// EqRef matches rows where r equals another column of the same type, e.g. for joins.
+func (r Ref[T]) EqRef(other Ref[T]) filter.Filter {
+	return filter.ExprEquals(r, other)
+}
+
+func (r Ref[T]) Asc() filter.Order {
+	return filter.OrderExprAsc(r)
+}
+
+func (r Ref[T]) Desc() filter.Order {
+	return filter.OrderExprDesc(r)
+}
+
+// To assigns val to the column, for use with update.Builder.Set.
+func (r Ref[T]) To(val T) Assignment {
+	return Assignment{
+		Column: r.name,
+		Value:  val,
+	}
+}
+
+// Assignment is a value for a column in an UPDATE.
+type Assignment struct {
+	Column string
+	Value  any
+}
+
+// Reference is implemented by Ref of any type.
+type Reference interface {
+	ast.IntoExpr
+	Name() string
+}
+
+// Names returns the unqualified names of refs, e.g. for insert.Builder.Columns.
+func Names(refs ...Reference) []string {
+	names := make([]string, 0, len(refs))
+	for _, r := range refs {
+		names = append(names, r.Name())
+	}
+	return names
+}
@@ -0,0 +1,43 @@
+package column
+
+import (
+	"time"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+type timeColumnBuilder[U columnTyper] struct {
+	*baseColumnBuilder[time.Time, U]
+	onUpdateCurrentTimestamp bool
+}
+
+func newTimeColumnBuilder[U columnTyper](name string, parent U) *timeColumnBuilder[U] {
+	return &timeColumnBuilder[U]{
+		baseColumnBuilder: newBaseColumnBuilder[time.Time](name, parent),
+	}
+}
+
+// DefaultCurrentTimestamp sets the column's default to CURRENT_TIMESTAMP.
+func (b *timeColumnBuilder[U]) DefaultCurrentTimestamp() U {
+	return b.DefaultExpr(ast.NewCurrentTimestampLiteral())
+}
+
+// OnUpdateCurrentTimestamp makes MySQL set the column to CURRENT_TIMESTAMP whenever the row is
+// updated. SQLite has no equivalent, so it is omitted from SQLite's CREATE TABLE.
+func (b *timeColumnBuilder[U]) OnUpdateCurrentTimestamp() U {
+	b.onUpdateCurrentTimestamp = true
+	return b.parent
+}
+
+func (b *timeColumnBuilder[U]) Build() *ast.ColumnSpec {
+	cs := b.baseColumnBuilder.Build()
+
+	if b.defaultVal != nil {
+		cs.WithDefault(ast.NewTimeLiteral(*b.defaultVal))
+	}
+	if b.onUpdateCurrentTimestamp {
+		cs.WithOnUpdate(ast.NewCurrentTimestampLiteral())
+	}
+
+	return cs
+}
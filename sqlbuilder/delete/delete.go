@@ -15,7 +15,7 @@ import (
 )
 
 type Formatter interface {
-	FormatNode(w io.Writer, n ast.Node)
+	FormatNode(w io.Writer, n ast.Node) error
 }
 
 type Builder struct {
@@ -23,6 +23,8 @@ type Builder struct {
 	f     Formatter
 
 	orderBy *filter.Order
+	all     bool
+	hooks   []dispatch.Hook
 	*condition.ConditionBuilder[*Builder]
 	*limit.LimitBuilder[*Builder]
 }
@@ -37,31 +39,53 @@ func NewBuilder(f Formatter, table ast.IntoTableExpr) *Builder {
 	return b
 }
 
+// All allows the delete to remove every row in the table. Without it, a delete with no WHERE
+// condition (or a condition which is always true) fails to build.
+func (b *Builder) All() *Builder {
+	b.all = true
+	return b
+}
+
 func (b *Builder) Build() (statement.Statement, error) {
 	n := ast.NewDelete(b.table.IntoTableExpr())
 
 	n.WithWhere(b.ConditionBuilder)
+	if n.Where == nil && !b.all {
+		return statement.Statement{}, &ast.BuildError{
+			Clause: `WHERE`,
+			Node:   n,
+			Reason: `refusing to delete every row without a condition; use All to allow it`,
+		}
+	}
 
 	offset, limit := b.LimitBuilder.OffsetAndLimit()
 	n.WithLimit(offset, limit)
 
 	if b.orderBy != nil {
-		n.WithOrders(ast.NewOrder(ast.NewIdentifier(b.orderBy.Column), b.orderBy.Direction.ToASTDirection()))
+		n.WithOrders(ast.NewOrder(b.orderBy.ToASTExpr(), b.orderBy.Direction.ToASTDirection()))
 	}
 
 	sb := &strings.Builder{}
-	b.f.FormatNode(sb, n)
+	if err := b.f.FormatNode(sb, n); err != nil {
+		return statement.Statement{}, err
+	}
 
 	return statement.Statement{
 		Stmt: sb.String(),
-		Args: ast.GetArgs(n),
+		Args: ast.GetArgsFor(b.f, n),
 	}, nil
 }
 
+// WithHooks adds hooks, which wrap each statement the builder runs.
+func (b *Builder) WithHooks(hs ...dispatch.Hook) *Builder {
+	b.hooks = append(b.hooks, hs...)
+	return b
+}
+
 func (b *Builder) Exec(e dispatch.Execer) (sql.Result, error) {
-	return dispatch.Exec(b, e)
+	return dispatch.Exec(b, e, b.hooks...)
 }
 
 func (b *Builder) ExecContext(ctx context.Context, e dispatch.ExecCtxer) (sql.Result, error) {
-	return dispatch.ExecContext(ctx, b, e)
+	return dispatch.ExecContext(ctx, b, e, b.hooks...)
 }
@@ -0,0 +1,71 @@
+// Package expr contains builders for expressions which aren't tied to a particular statement, for use
+// in select lists, filters, ORDER BY, UPDATE assignments and function arguments.
+package expr
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+
+type when struct {
+	cond   ast.IntoExpr
+	result ast.IntoExpr
+}
+
+// CaseBuilder builds a CASE expression.
+type CaseBuilder struct {
+	operand ast.IntoExpr
+	whens   []when
+	els     ast.IntoExpr
+}
+
+// Case starts a searched CASE expression, where each When takes a condition (usually a filter):
+//
+//	CASE WHEN a > ? THEN ? ELSE ? END
+func Case() *CaseBuilder {
+	return &CaseBuilder{}
+}
+
+// CaseOf starts a simple CASE expression, where each When takes a value to compare operand with:
+//
+//	CASE a WHEN ? THEN ? ELSE ? END
+func CaseOf(operand ast.IntoExpr) *CaseBuilder {
+	return &CaseBuilder{
+		operand: operand,
+	}
+}
+
+// When adds a branch. cond and result can be expressions (filters, columns, functions...); anything else
+// is passed as a placeholder value.
+func (b *CaseBuilder) When(cond, result any) *CaseBuilder {
+	b.whens = append(b.whens, when{
+		cond:   valueExpr(cond),
+		result: valueExpr(result),
+	})
+	return b
+}
+
+// Else sets the result when no branch matches. Without it, the result is NULL.
+func (b *CaseBuilder) Else(result any) *CaseBuilder {
+	b.els = valueExpr(result)
+	return b
+}
+
+func (b *CaseBuilder) IntoExpr() ast.Expr {
+	c := ast.NewCase(b.operand)
+	for _, w := range b.whens {
+		c.AddWhen(w.cond, w.result)
+	}
+	if b.els != nil {
+		c.WithElse(b.els)
+	}
+	return c
+}
+
+func (b *CaseBuilder) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(b, alias)
+}
+
+func valueExpr(v any) ast.IntoExpr {
+	if e, ok := v.(ast.IntoExpr); ok {
+		return e
+	}
+	return ast.NewPlaceholderLiteral(v)
+}
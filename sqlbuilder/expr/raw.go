@@ -0,0 +1,34 @@
+package expr
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+
+// RawExpr is a fragment of handwritten SQL. See Raw.
+type RawExpr struct {
+	sql  string
+	args []any
+}
+
+// Raw embeds handwritten SQL in a statement, for when the builders don't support something. It can be
+// used anywhere an expression, filter or table expression is accepted. Each ? in sql (outside of quotes)
+// is a placeholder for the next of args; building the statement fails if the counts don't match.
+//
+// Raw SQL is parenthesized when it's used as an operand, e.g. in filter.All, so it can't change the
+// meaning of the surrounding expression.
+func Raw(sql string, args ...any) RawExpr {
+	return RawExpr{
+		sql:  sql,
+		args: args,
+	}
+}
+
+func (r RawExpr) IntoExpr() ast.Expr {
+	return ast.NewRaw(r.sql, r.args...)
+}
+
+func (r RawExpr) IntoTableExpr() ast.TableExpr {
+	return ast.NewRaw(r.sql, r.args...)
+}
+
+func (r RawExpr) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(r, alias)
+}
@@ -0,0 +1,98 @@
+package filter
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+
+// ExprFilter compares two arbitrary expressions. Unlike the filters which take a column name and a
+// value, either side can be a qualified column (column.Named("id").QualifiedBy("u")), a function call,
+// a subquery (any *sel.Builder) or a placeholder made with Value.
+type ExprFilter struct {
+	left  ast.IntoExpr
+	right ast.IntoExpr
+	op    ast.BinaryExprOperator
+}
+
+func (f ExprFilter) IntoExpr() ast.Expr {
+	right := f.right.IntoExpr()
+	if t, ok := right.(*ast.TupleLiteral); ok && len(t.Values) == 0 && (f.op == ast.BinaryIn || f.op == ast.BinaryNotIn) {
+		// Like In and NotIn, an empty list matches nothing (or everything, when negated).
+		return ast.NewBoolLiteral(f.op == ast.BinaryNotIn)
+	}
+
+	return &ast.BinaryExpr{
+		Left:  f.left.IntoExpr(),
+		Op:    f.op,
+		Right: right,
+	}
+}
+
+func ExprEquals(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryEquals}
+}
+
+func ExprNotEquals(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryNotEquals}
+}
+
+func ExprGreater(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryGreater}
+}
+
+func ExprGreaterOrEqual(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryGreaterOrEqual}
+}
+
+func ExprLess(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryLess}
+}
+
+func ExprLessOrEqual(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryLessOrEqual}
+}
+
+// ExprIn matches rows where left is one of the rows returned by the subquery right.
+func ExprIn(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryIn}
+}
+
+// ExprNotIn matches rows where left is none of the rows returned by the subquery right.
+func ExprNotIn(left, right ast.IntoExpr) ExprFilter {
+	return ExprFilter{left: left, right: right, op: ast.BinaryNotIn}
+}
+
+// ExprBetweenFilter checks whether an expression is between two others. See ExprBetween.
+type ExprBetweenFilter struct {
+	expr ast.IntoExpr
+	low  ast.IntoExpr
+	high ast.IntoExpr
+	op   ast.TernaryExprOperator
+}
+
+func (f ExprBetweenFilter) IntoExpr() ast.Expr {
+	return ast.NewTernaryExpr(f.expr, f.op, f.low, f.high)
+}
+
+// ExprBetween matches rows where e is between low and high, inclusive. Like the other Expr filters,
+// the bounds are expressions; use Value for a placeholder, or ExprBetweenValues to pass plain values.
+func ExprBetween(e, low, high ast.IntoExpr) ExprBetweenFilter {
+	return ExprBetweenFilter{expr: e, low: low, high: high, op: ast.TernaryBetween}
+}
+
+// ExprNotBetween matches rows where e is less than low or greater than high.
+func ExprNotBetween(e, low, high ast.IntoExpr) ExprBetweenFilter {
+	return ExprBetweenFilter{expr: e, low: low, high: high, op: ast.TernaryNotBetween}
+}
+
+// ExprInValues matches rows where left equals one of vals.
+func ExprInValues[T any](left ast.IntoExpr, vals ...T) ExprFilter {
+	return ExprFilter{left: left, right: placeholderTuple(vals), op: ast.BinaryIn}
+}
+
+// ExprNotInValues matches rows where left equals none of vals.
+func ExprNotInValues[T any](left ast.IntoExpr, vals ...T) ExprFilter {
+	return ExprFilter{left: left, right: placeholderTuple(vals), op: ast.BinaryNotIn}
+}
+
+// Value is a placeholder for val, for comparing it with an expression in an ExprFilter.
+func Value[T any](val T) ast.IntoExpr {
+	return ast.NewPlaceholderLiteral(val)
+}
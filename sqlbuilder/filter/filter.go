@@ -12,6 +12,7 @@ type AllFilter struct {
 	Filters []Filter
 }
 
+// All matches rows which match every one of fs. With no filters, All matches every row.
 func All(fs ...Filter) AllFilter {
 	return AllFilter{
 		Filters: fs,
@@ -19,20 +20,47 @@ func All(fs ...Filter) AllFilter {
 }
 
 func (f AllFilter) IntoExpr() ast.Expr {
-	return makeChainedExpr(f.Filters[0], ast.BinaryAnd, f.Filters[1:]...).IntoExpr()
+	return makeChainedExpr(ast.BinaryAnd, f.Filters)
 }
 
-func makeChainedExpr(left Filter, op ast.BinaryExprOperator, rest ...Filter) ast.IntoExpr {
-	if len(rest) == 0 {
-		return left
+// makeChainedExpr joins fs with op, which is either AND or OR. Constant operands are simplified away:
+// TRUE is the identity of AND and absorbs OR, and FALSE is the other way around. An empty chain is the
+// identity of op.
+func makeChainedExpr(op ast.BinaryExprOperator, fs []Filter) ast.Expr {
+	identity := op == ast.BinaryAnd
+
+	exprs := make([]ast.Expr, 0, len(fs))
+	for _, f := range fs {
+		e := f.IntoExpr()
+		if b, ok := e.(*ast.BoolLiteral); ok {
+			if b.Value == identity {
+				continue
+			}
+			return ast.NewBoolLiteral(!identity)
+		}
+		exprs = append(exprs, e)
+	}
+
+	if len(exprs) == 0 {
+		return ast.NewBoolLiteral(identity)
+	}
+
+	res := exprs[len(exprs)-1]
+	for i := len(exprs) - 2; i >= 0; i-- {
+		res = &ast.BinaryExpr{
+			Left:  exprs[i],
+			Op:    op,
+			Right: res,
+		}
 	}
-	return ast.NewBinaryExpr(left, op, makeChainedExpr(rest[0], op, rest[1:]...))
+	return res
 }
 
 type AnyFilter struct {
 	Filters []Filter
 }
 
+// Any matches rows which match at least one of fs. With no filters, Any matches nothing.
 func Any(fs ...Filter) AnyFilter {
 	return AnyFilter{
 		Filters: fs,
@@ -40,7 +68,7 @@ func Any(fs ...Filter) AnyFilter {
 }
 
 func (f AnyFilter) IntoExpr() ast.Expr {
-	return makeChainedExpr(f.Filters[0], ast.BinaryOr, f.Filters[1:]...).IntoExpr()
+	return makeChainedExpr(ast.BinaryOr, f.Filters)
 }
 
 type BinOpFilter[T any] struct {
@@ -114,6 +142,7 @@ func LessOrEqual[T any](column string, val T) BinOpFilter[T] {
 type InFilter[T any] struct {
 	Column string
 	Values []T
+	Not    bool
 }
 
 func In[T any](column string, vals ...T) InFilter[T] {
@@ -123,27 +152,221 @@ func In[T any](column string, vals ...T) InFilter[T] {
 	}
 }
 
+func NotIn[T any](column string, vals ...T) InFilter[T] {
+	return InFilter[T]{
+		Column: column,
+		Values: vals,
+		Not:    true,
+	}
+}
+
 func (f InFilter[T]) IntoExpr() ast.Expr {
+	// An empty IN () is a syntax error. Nothing is in an empty list, so IN matches no rows and NOT IN
+	// matches all of them.
+	if len(f.Values) == 0 {
+		return ast.NewBoolLiteral(f.Not)
+	}
+
 	exprs := make([]ast.IntoExpr, 0, len(f.Values))
 	for _, val := range f.Values {
 		exprs = append(exprs, ast.NewPlaceholderLiteral(val))
 	}
-	return ast.NewBinaryExpr(ast.NewIdentifier(f.Column), ast.BinaryIn, ast.NewTupleLiteral(exprs...))
+
+	op := ast.BinaryIn
+	if f.Not {
+		op = ast.BinaryNotIn
+	}
+	return ast.NewBinaryExpr(ast.NewIdentifier(f.Column), op, ast.NewTupleLiteral(exprs...))
+}
+
+type TupleInFilter struct {
+	Columns []string
+	Values  [][]any
+	Not     bool
+}
+
+// TupleIn matches rows where the columns, taken together, equal one of the given tuples of values. For
+// example, TupleIn([]string{"a", "b"}, []any{1, 2}, []any{3, 4}) renders (a,b) IN ((?,?),(?,?)). Each
+// tuple must have one value per column.
+func TupleIn(columns []string, vals ...[]any) TupleInFilter {
+	return TupleInFilter{
+		Columns: columns,
+		Values:  vals,
+	}
+}
+
+// TupleNotIn matches rows where the columns, taken together, equal none of the given tuples of values.
+func TupleNotIn(columns []string, vals ...[]any) TupleInFilter {
+	return TupleInFilter{
+		Columns: columns,
+		Values:  vals,
+		Not:     true,
+	}
+}
+
+func (f TupleInFilter) IntoExpr() ast.Expr {
+	if len(f.Values) == 0 {
+		return ast.NewBoolLiteral(f.Not)
+	}
+
+	rows := make([]ast.IntoExpr, 0, len(f.Values))
+	for _, vals := range f.Values {
+		rows = append(rows, placeholderTuple(vals))
+	}
+
+	op := ast.BinaryIn
+	if f.Not {
+		op = ast.BinaryNotIn
+	}
+	return ast.NewBinaryExpr(identifierTuple(f.Columns), op, ast.NewTupleLiteral(rows...))
+}
+
+type TupleCompareFilter struct {
+	columns []string
+	values  []any
+	op      ast.BinaryExprOperator
+}
+
+func (f TupleCompareFilter) IntoExpr() ast.Expr {
+	return ast.NewBinaryExpr(identifierTuple(f.columns), f.op, placeholderTuple(f.values))
+}
+
+// TupleEquals matches rows where every column equals the corresponding value.
+func TupleEquals(columns []string, vals ...any) TupleCompareFilter {
+	return TupleCompareFilter{columns: columns, values: vals, op: ast.BinaryEquals}
+}
+
+// TupleNotEquals matches rows where any column differs from the corresponding value.
+func TupleNotEquals(columns []string, vals ...any) TupleCompareFilter {
+	return TupleCompareFilter{columns: columns, values: vals, op: ast.BinaryNotEquals}
+}
+
+// TupleGreater compares the columns with the values lexicographically, so (a,b) > (1,2) matches rows
+// where a > 1, or where a = 1 and b > 2. This is handy for keyset pagination.
+func TupleGreater(columns []string, vals ...any) TupleCompareFilter {
+	return TupleCompareFilter{columns: columns, values: vals, op: ast.BinaryGreater}
+}
+
+// TupleGreaterOrEqual is like TupleGreater, but also matches rows equal to the values.
+func TupleGreaterOrEqual(columns []string, vals ...any) TupleCompareFilter {
+	return TupleCompareFilter{columns: columns, values: vals, op: ast.BinaryGreaterOrEqual}
+}
+
+// TupleLess compares the columns with the values lexicographically. See TupleGreater.
+func TupleLess(columns []string, vals ...any) TupleCompareFilter {
+	return TupleCompareFilter{columns: columns, values: vals, op: ast.BinaryLess}
+}
+
+// TupleLessOrEqual is like TupleLess, but also matches rows equal to the values.
+func TupleLessOrEqual(columns []string, vals ...any) TupleCompareFilter {
+	return TupleCompareFilter{columns: columns, values: vals, op: ast.BinaryLessOrEqual}
+}
+
+func identifierTuple(columns []string) *ast.TupleLiteral {
+	exprs := make([]ast.IntoExpr, 0, len(columns))
+	for _, col := range columns {
+		exprs = append(exprs, ast.NewIdentifier(col))
+	}
+	return ast.NewTupleLiteral(exprs...)
+}
+
+func placeholderTuple[T any](vals []T) *ast.TupleLiteral {
+	exprs := make([]ast.IntoExpr, 0, len(vals))
+	for _, val := range vals {
+		exprs = append(exprs, ast.NewPlaceholderLiteral(val))
+	}
+	return ast.NewTupleLiteral(exprs...)
+}
+
+type BetweenFilter[T any] struct {
+	expr ast.IntoExpr
+	low  T
+	high T
+	op   ast.TernaryExprOperator
+}
+
+// Between matches rows where column is between low and high, inclusive.
+func Between[T any](column string, low, high T) BetweenFilter[T] {
+	return ExprBetweenValues(ast.NewIdentifier(column), low, high)
+}
+
+// ExprBetweenValues is like Between, but for an arbitrary expression instead of a column. To compare
+// with bounds which are expressions themselves, use ExprBetween.
+func ExprBetweenValues[T any](e ast.IntoExpr, low, high T) BetweenFilter[T] {
+	return BetweenFilter[T]{
+		expr: e,
+		low:  low,
+		high: high,
+		op:   ast.TernaryBetween,
+	}
+}
+
+// NotBetween matches rows where column is less than low or greater than high.
+func NotBetween[T any](column string, low, high T) BetweenFilter[T] {
+	return ExprNotBetweenValues(ast.NewIdentifier(column), low, high)
+}
+
+// ExprNotBetweenValues is like NotBetween, but for an arbitrary expression instead of a column.
+func ExprNotBetweenValues[T any](e ast.IntoExpr, low, high T) BetweenFilter[T] {
+	return BetweenFilter[T]{
+		expr: e,
+		low:  low,
+		high: high,
+		op:   ast.TernaryNotBetween,
+	}
+}
+
+func (f BetweenFilter[T]) IntoExpr() ast.Expr {
+	return ast.NewTernaryExpr(
+		f.expr,
+		f.op,
+		ast.NewPlaceholderLiteral(f.low),
+		ast.NewPlaceholderLiteral(f.high),
+	)
+}
+
+type NotFilter struct {
+	Filter Filter
+}
+
+// Not negates f.
+func Not(f Filter) NotFilter {
+	return NotFilter{
+		Filter: f,
+	}
+}
+
+func (f NotFilter) IntoExpr() ast.Expr {
+	e := f.Filter.IntoExpr()
+	if b, ok := e.(*ast.BoolLiteral); ok {
+		return ast.NewBoolLiteral(!b.Value)
+	}
+	return ast.NewUnaryExpr(e, ast.UnaryNot)
 }
 
 type NullFilter struct {
-	column string
-	op     ast.UnaryExprOperator
+	expr ast.IntoExpr
+	op   ast.UnaryExprOperator
 }
 
 func IsNull(column string) NullFilter {
-	return NullFilter{column: column, op: ast.UnaryIsNull}
+	return ExprIsNull(ast.NewIdentifier(column))
 }
 
 func IsNotNull(column string) NullFilter {
-	return NullFilter{column: column, op: ast.UnaryIsNotNull}
+	return ExprIsNotNull(ast.NewIdentifier(column))
+}
+
+// ExprIsNull is like IsNull, but for an arbitrary expression instead of a column.
+func ExprIsNull(e ast.IntoExpr) NullFilter {
+	return NullFilter{expr: e, op: ast.UnaryIsNull}
+}
+
+// ExprIsNotNull is like IsNotNull, but for an arbitrary expression instead of a column.
+func ExprIsNotNull(e ast.IntoExpr) NullFilter {
+	return NullFilter{expr: e, op: ast.UnaryIsNotNull}
 }
 
 func (f NullFilter) IntoExpr() ast.Expr {
-	return ast.NewUnaryExpr(ast.NewIdentifier(f.column), f.op)
+	return ast.NewUnaryExpr(f.expr.IntoExpr(), f.op)
 }
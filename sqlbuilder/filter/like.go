@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+// LikeEscapeChar is the escape character used by EscapeLike and the search helpers (StartsWith,
+// EndsWith, Contains).
+const LikeEscapeChar = '\\'
+
+type LikeFilter struct {
+	column  string
+	pattern string
+	escape  rune
+	not     bool
+}
+
+// Like matches rows where column matches pattern, in which % matches any sequence of characters and _
+// matches any single character. Use EscapeLike to match user input literally.
+func Like(column, pattern string) LikeFilter {
+	return LikeFilter{
+		column:  column,
+		pattern: pattern,
+	}
+}
+
+// NotLike matches rows where column does not match pattern. See Like.
+func NotLike(column, pattern string) LikeFilter {
+	return LikeFilter{
+		column:  column,
+		pattern: pattern,
+		not:     true,
+	}
+}
+
+// StartsWith matches rows where column starts with prefix. prefix is matched literally.
+func StartsWith(column, prefix string) LikeFilter {
+	return Like(column, EscapeLike(prefix)+`%`).Escape(LikeEscapeChar)
+}
+
+// EndsWith matches rows where column ends with suffix. suffix is matched literally.
+func EndsWith(column, suffix string) LikeFilter {
+	return Like(column, `%`+EscapeLike(suffix)).Escape(LikeEscapeChar)
+}
+
+// Contains matches rows where column contains substr. substr is matched literally.
+func Contains(column, substr string) LikeFilter {
+	return Like(column, `%`+EscapeLike(substr)+`%`).Escape(LikeEscapeChar)
+}
+
+// Escape sets the character used to escape wildcards in the pattern. SQLite has no default escape
+// character, so patterns produced by EscapeLike need Escape(LikeEscapeChar).
+func (f LikeFilter) Escape(c rune) LikeFilter {
+	f.escape = c
+	return f
+}
+
+func (f LikeFilter) IntoExpr() ast.Expr {
+	col := ast.NewIdentifier(f.column)
+	pattern := ast.NewPlaceholderLiteral(f.pattern)
+
+	if f.escape == 0 {
+		op := ast.BinaryLike
+		if f.not {
+			op = ast.BinaryNotLike
+		}
+		return ast.NewBinaryExpr(col, op, pattern)
+	}
+
+	op := ast.TernaryLikeEscape
+	if f.not {
+		op = ast.TernaryNotLikeEscape
+	}
+	return ast.NewTernaryExpr(col, op, pattern, ast.NewStringLiteral(string(f.escape)))
+}
+
+var likeEscaper = strings.NewReplacer(
+	string(LikeEscapeChar), string(LikeEscapeChar)+string(LikeEscapeChar),
+	`%`, string(LikeEscapeChar)+`%`,
+	`_`, string(LikeEscapeChar)+`_`,
+)
+
+// EscapeLike escapes the LIKE wildcards in s with LikeEscapeChar so that s matches literally. The
+// filter using the result must set Escape(LikeEscapeChar).
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -16,12 +16,14 @@ func (d Direction) ToASTDirection() ast.OrderDirection {
 	case Descending:
 		return ast.OrderDesc
 	}
-	panic(`unreachable`)
+	return ast.OrderInvalid
 }
 
 type Order struct {
 	Column    string
 	Direction Direction
+
+	expr ast.IntoExpr
 }
 
 func OrderDesc(field string) Order {
@@ -37,3 +39,27 @@ func OrderAsc(field string) Order {
 		Direction: Ascending,
 	}
 }
+
+// OrderExprDesc orders by an arbitrary expression (e.g. a qualified column), descending.
+func OrderExprDesc(e ast.IntoExpr) Order {
+	return Order{
+		Direction: Descending,
+		expr:      e,
+	}
+}
+
+// OrderExprAsc orders by an arbitrary expression (e.g. a qualified column), ascending.
+func OrderExprAsc(e ast.IntoExpr) Order {
+	return Order{
+		Direction: Ascending,
+		expr:      e,
+	}
+}
+
+// ToASTExpr returns the expression being ordered by.
+func (o Order) ToASTExpr() ast.IntoExpr {
+	if o.expr != nil {
+		return o.expr
+	}
+	return ast.NewIdentifier(o.Column)
+}
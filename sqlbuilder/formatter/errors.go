@@ -0,0 +1,33 @@
+package formatter
+
+import (
+	"fmt"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+// formatError unwinds a (possibly deeply nested) format call when a node can't be expressed in the
+// formatter's dialect. FormatNode recovers it and returns the wrapped error.
+type formatError struct {
+	err *ast.BuildError
+}
+
+func failf(clause string, n ast.Node, format string, args ...any) {
+	panic(formatError{err: &ast.BuildError{
+		Clause: clause,
+		Node:   n,
+		Reason: fmt.Sprintf(format, args...),
+	}})
+}
+
+func recoverFormatError(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if fe, ok := r.(formatError); ok {
+		*err = fe.err
+		return
+	}
+	panic(r)
+}
@@ -1,18 +1,24 @@
 package formatter
 
 import (
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cszczepaniak/gotest/assert"
 
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/filter"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/functions"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/statement"
 )
 
 type formatter interface {
-	FormatNode(w io.Writer, n ast.Node)
+	FormatNode(w io.Writer, n ast.Node) error
 }
 
 type formatTestCase struct {
@@ -35,12 +41,25 @@ func assertFormatting(t *testing.T, cases ...formatTestCase) {
 	for _, c := range cases {
 		t.Run(fmt.Sprintf("%T", c.f), func(t *testing.T) {
 			sb := &strings.Builder{}
-			c.f.FormatNode(sb, c.node)
+			assert.NoError(t, c.f.FormatNode(sb, c.node))
 			assert.Equal(t, sb.String(), c.exp)
 		})
 	}
 }
 
+func assertFormattingError(t *testing.T, f formatter, node ast.Node) {
+	t.Helper()
+
+	t.Run(fmt.Sprintf("%T", f), func(t *testing.T) {
+		sb := &strings.Builder{}
+		err := f.FormatNode(sb, node)
+		assert.Error(t, err)
+
+		var be *ast.BuildError
+		assert.Equal(t, errors.As(err, &be), true)
+	})
+}
+
 func assertAllFormatting(t *testing.T, node ast.Node, exp string) {
 	t.Helper()
 
@@ -68,3 +87,700 @@ func TestSelector(t *testing.T) {
 
 	assertAllFormatting(t, node, "foo.bar")
 }
+
+func TestColumnDefaults(t *testing.T) {
+	ts := time.Date(2024, time.March, 4, 5, 6, 7, 890000000, time.UTC)
+
+	assertFormatting(t,
+		newFormatTestCase(
+			Mysql{},
+			ast.NewColumnSpec("created_at", ast.DateTime()).WithDefault(ast.NewCurrentTimestampLiteral()),
+			`created_at DATETIME DEFAULT CURRENT_TIMESTAMP`,
+		),
+		newFormatTestCase(
+			Sqlite{},
+			ast.NewColumnSpec("created_at", ast.DateTime()).WithDefault(ast.NewCurrentTimestampLiteral()),
+			`created_at NUMERIC DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ','now'))`,
+		),
+	)
+
+	assertFormatting(t,
+		newFormatTestCase(
+			Mysql{},
+			ast.NewColumnSpec("updated_at", ast.Timestamp()).
+				WithDefault(ast.NewCurrentTimestampLiteral()).
+				WithOnUpdate(ast.NewCurrentTimestampLiteral()),
+			`updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP`,
+		),
+		newFormatTestCase(
+			Sqlite{},
+			ast.NewColumnSpec("updated_at", ast.Timestamp()).
+				WithDefault(ast.NewCurrentTimestampLiteral()).
+				WithOnUpdate(ast.NewCurrentTimestampLiteral()),
+			`updated_at NUMERIC DEFAULT (STRFTIME('%Y-%m-%dT%H:%M:%fZ','now'))`,
+		),
+	)
+
+	assertFormatting(t,
+		newFormatTestCase(
+			Mysql{},
+			ast.NewColumnSpec("at", ast.DateTime()).WithDefault(ast.NewTimeLiteral(ts.In(time.FixedZone("", 3600)))),
+			`at DATETIME DEFAULT '2024-03-04 05:06:07.89'`,
+		),
+		// SQLite times are written in UTC with a fixed width, so that they compare correctly as text.
+		newFormatTestCase(
+			Sqlite{},
+			ast.NewColumnSpec("at", ast.DateTime()).WithDefault(ast.NewTimeLiteral(ts.In(time.FixedZone("", 3600)))),
+			`at NUMERIC DEFAULT '2024-03-04T05:06:07.890Z'`,
+		),
+	)
+
+	assertAllFormatting(t,
+		ast.NewColumnSpec("id", ast.Blob()).WithDefault(ast.NewFunction("RANDOMBLOB", ast.NewIntegerLiteral(16))),
+		`id BLOB DEFAULT (RANDOMBLOB(16))`,
+	)
+}
+
+func TestGeneratedColumns(t *testing.T) {
+	assertFormatting(t,
+		newFormatTestCase(
+			Mysql{},
+			ast.NewColumnSpec("b_copy", ast.Int()).
+				WithGenerated(ast.NewIdentifier("b"), true).
+				WithNullabilityFromBool(new(bool)),
+			`b_copy INT GENERATED ALWAYS AS (b) STORED NOT NULL`,
+		),
+		newFormatTestCase(
+			Sqlite{},
+			ast.NewColumnSpec("b_copy", ast.Int()).
+				WithGenerated(ast.NewIdentifier("b"), true).
+				WithNullabilityFromBool(new(bool)),
+			`b_copy INTEGER GENERATED ALWAYS AS (b) STORED NOT NULL`,
+		),
+	)
+
+	assertAllFormatting(t,
+		ast.NewColumnSpec("b_copy", ast.Blob()).WithGenerated(ast.NewIdentifier("b"), false),
+		`b_copy BLOB GENERATED ALWAYS AS (b) VIRTUAL`,
+	)
+}
+
+func TestCreateTableOptions(t *testing.T) {
+	newCreateTable := func(opts ...*ast.TableOption) *ast.CreateTable {
+		ct := ast.NewCreateTable("foo")
+		ct.AddColumn(ast.NewColumnSpec("id", ast.VarChar(32)).WithCollation("utf8mb4_bin").SetPrimaryKey(true))
+		for _, opt := range opts {
+			ct.AddOption(opt)
+		}
+		return ct
+	}
+
+	assertFormatting(t,
+		newFormatTestCase(
+			Mysql{},
+			newCreateTable(
+				ast.NewTableOption(ast.TableOptionEngine, "InnoDB"),
+				ast.NewTableOption(ast.TableOptionCharset, "utf8mb4"),
+				ast.NewTableOption(ast.TableOptionCollate, "utf8mb4_unicode_ci"),
+				ast.NewTableOption(ast.TableOptionComment, "it's a table"),
+			),
+			`CREATE TABLE foo(id VARCHAR(32) COLLATE utf8mb4_bin,PRIMARY KEY (id)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='it''s a table'`,
+		),
+		newFormatTestCase(
+			Sqlite{},
+			newCreateTable(
+				ast.NewTableOption(ast.TableOptionWithoutRowID, ""),
+				ast.NewTableOption(ast.TableOptionStrict, ""),
+			),
+			`CREATE TABLE foo(id TEXT COLLATE utf8mb4_bin PRIMARY KEY) WITHOUT ROWID,STRICT`,
+		),
+	)
+
+	assertFormattingError(t, Mysql{}, newCreateTable(ast.NewTableOption(ast.TableOptionStrict, "")))
+	assertFormattingError(t, Mysql{}, newCreateTable(ast.NewTableOption(ast.TableOptionWithoutRowID, "")))
+	assertFormattingError(t, Sqlite{}, newCreateTable(ast.NewTableOption(ast.TableOptionEngine, "InnoDB")))
+	assertFormattingError(t, Sqlite{}, newCreateTable(ast.NewTableOption(ast.TableOptionComment, "foo")))
+
+	withComment := ast.NewCreateTable("foo")
+	withComment.AddColumn(ast.NewColumnSpec("id", ast.Int()).WithComment(`a \ b`))
+	assertFormatting(t, newFormatTestCase(Mysql{}, withComment, `CREATE TABLE foo(id INT COMMENT 'a \\ b')`))
+	assertFormattingError(t, Sqlite{}, withComment)
+
+	strictDateTime := ast.NewCreateTable("foo")
+	strictDateTime.AddColumn(ast.NewColumnSpec("at", ast.DateTime()))
+	strictDateTime.AddOption(ast.NewTableOption(ast.TableOptionStrict, ""))
+	assertFormattingError(t, Sqlite{}, strictDateTime)
+}
+
+func TestCreateTableAsSelect(t *testing.T) {
+	ct := ast.NewCreateTable("foo")
+	ct.CreateIfNotExists()
+	ct.AsSelect = ast.NewSelect(ast.NewTableName("bar"), ast.NewIdentifier("a")).
+		WithWhere(ast.NewBinaryExpr(ast.NewIdentifier("b"), ast.BinaryEquals, ast.NewPlaceholderLiteral(1)))
+
+	assertAllFormatting(t, ct, `CREATE TABLE IF NOT EXISTS foo AS SELECT a FROM bar WHERE b = ?`)
+
+	ct.AddOption(ast.NewTableOption(ast.TableOptionEngine, "InnoDB"))
+	assertFormatting(t, newFormatTestCase(Mysql{}, ct, `CREATE TABLE IF NOT EXISTS foo ENGINE=InnoDB AS SELECT a FROM bar WHERE b = ?`))
+	assertFormattingError(t, Sqlite{}, ct)
+}
+
+func TestCreateTableLike(t *testing.T) {
+	ct := ast.NewCreateTable("foo")
+	ct.Like = ast.NewTableName("bar")
+
+	assertFormatting(t, newFormatTestCase(Mysql{}, ct, `CREATE TABLE foo LIKE bar`))
+
+	// SQLite needs the definition of the other table.
+	assertFormattingError(t, Sqlite{}, ct)
+
+	for _, def := range []string{
+		`CREATE TABLE bar(a INT NOT NULL PRIMARY KEY) WITHOUT ROWID`,
+		`CREATE TABLE "bar"(a INT NOT NULL PRIMARY KEY) WITHOUT ROWID`,
+		`CREATE TABLE [bar](a INT NOT NULL PRIMARY KEY) WITHOUT ROWID`,
+	} {
+		ct.LikeDefinition = def
+		assertFormatting(t, newFormatTestCase(Sqlite{}, ct, `CREATE TABLE foo(a INT NOT NULL PRIMARY KEY) WITHOUT ROWID`))
+	}
+
+	ct.LikeDefinition = `CREATE VIEW bar AS SELECT 1`
+	assertFormattingError(t, Sqlite{}, ct)
+
+	// The table package reads the definition for formatters which ask for it.
+	type likeEmulator interface{ EmulatesCreateLike() bool }
+	for _, f := range []any{Sqlite{}, &Sqlite{}, Pretty{Formatter: Sqlite{}}} {
+		e, ok := f.(likeEmulator)
+		assert.Equal(t, ok && e.EmulatesCreateLike(), true)
+	}
+	for _, f := range []any{Mysql{}, Pretty{Formatter: Mysql{}}} {
+		e, ok := f.(likeEmulator)
+		assert.Equal(t, ok && e.EmulatesCreateLike(), false)
+	}
+}
+
+func TestViews(t *testing.T) {
+	cv := ast.NewCreateView("foo", ast.NewSelect(ast.NewTableName("bar"), ast.NewIdentifier("a"), ast.NewIdentifier("b")))
+	cv.Columns = []*ast.Identifier{ast.NewIdentifier("x"), ast.NewIdentifier("y")}
+
+	assertAllFormatting(t, cv, `CREATE VIEW foo(x,y) AS SELECT a,b FROM bar`)
+
+	cv.OrReplace = true
+	assertFormatting(t, newFormatTestCase(Mysql{}, cv, `CREATE OR REPLACE VIEW foo(x,y) AS SELECT a,b FROM bar`))
+	assertFormattingError(t, Sqlite{}, cv)
+
+	cv.OrReplace = false
+	cv.IfNotExists = true
+	assertFormatting(t, newFormatTestCase(Sqlite{}, cv, `CREATE VIEW IF NOT EXISTS foo(x,y) AS SELECT a,b FROM bar`))
+	assertFormattingError(t, Mysql{}, cv)
+
+	dv := ast.NewDropView("foo")
+	assertAllFormatting(t, dv, `DROP VIEW foo`)
+
+	dv.IfExists = true
+	assertAllFormatting(t, dv, `DROP VIEW IF EXISTS foo`)
+}
+
+func TestSearchOperators(t *testing.T) {
+	col := ast.NewIdentifier("a")
+	ph := ast.NewPlaceholderLiteral
+
+	assertAllFormatting(t, ast.NewBinaryExpr(col, ast.BinaryLike, ph("x%")), `a LIKE ?`)
+	assertAllFormatting(t, ast.NewBinaryExpr(col, ast.BinaryNotLike, ph("x%")), `a NOT LIKE ?`)
+	assertAllFormatting(t, ast.NewBinaryExpr(col, ast.BinaryNotIn, ast.NewTupleLiteral(ph(1), ph(2))), `a NOT IN (?,?)`)
+	assertAllFormatting(t, ast.NewTernaryExpr(col, ast.TernaryBetween, ph(1), ph(2)), `a BETWEEN ? AND ?`)
+	assertAllFormatting(t, ast.NewTernaryExpr(col, ast.TernaryNotBetween, ph(1), ph(2)), `a NOT BETWEEN ? AND ?`)
+	assertAllFormatting(t, ast.NewTernaryExpr(col, ast.TernaryLikeEscape, ph("x%"), ast.NewStringLiteral("!")), `a LIKE ? ESCAPE '!'`)
+	assertAllFormatting(t, ast.NewTernaryExpr(col, ast.TernaryNotLikeEscape, ph("x%"), ast.NewStringLiteral("!")), `a NOT LIKE ? ESCAPE '!'`)
+	assertAllFormatting(t,
+		ast.NewUnaryExpr(ast.NewBinaryExpr(
+			ast.NewBinaryExpr(col, ast.BinaryEquals, ph(1)),
+			ast.BinaryOr,
+			ast.NewBinaryExpr(col, ast.BinaryEquals, ph(2)),
+		), ast.UnaryNot),
+		`NOT (a = ? OR a = ?)`,
+	)
+
+	// Backslashes are escapes in MySQL strings, but not in SQLite.
+	assertFormatting(t,
+		newFormatTestCase(Mysql{}, ast.NewTernaryExpr(col, ast.TernaryLikeEscape, ph("x%"), ast.NewStringLiteral(`\`)), `a LIKE ? ESCAPE '\\'`),
+		newFormatTestCase(Sqlite{}, ast.NewTernaryExpr(col, ast.TernaryLikeEscape, ph("x%"), ast.NewStringLiteral(`\`)), `a LIKE ? ESCAPE '\'`),
+	)
+}
+
+func TestPrecedence(t *testing.T) {
+	eq := func(col string, v int) *ast.BinaryExpr {
+		return ast.NewBinaryExpr(ast.NewIdentifier(col), ast.BinaryEquals, ast.NewPlaceholderLiteral(v))
+	}
+	and := func(l, r *ast.BinaryExpr) *ast.BinaryExpr { return ast.NewBinaryExpr(l, ast.BinaryAnd, r) }
+	or := func(l, r *ast.BinaryExpr) *ast.BinaryExpr { return ast.NewBinaryExpr(l, ast.BinaryOr, r) }
+
+	assertAllFormatting(t, and(or(eq("a", 1), eq("b", 2)), eq("c", 3)), `(a = ? OR b = ?) AND c = ?`)
+	assertAllFormatting(t, and(eq("a", 1), or(eq("b", 2), eq("c", 3))), `a = ? AND (b = ? OR c = ?)`)
+	assertAllFormatting(t, or(and(eq("a", 1), eq("b", 2)), eq("c", 3)), `a = ? AND b = ? OR c = ?`)
+
+	// AND and OR are associative, so chains of the same operator don't need parentheses.
+	assertAllFormatting(t, and(eq("a", 1), and(eq("b", 2), eq("c", 3))), `a = ? AND b = ? AND c = ?`)
+	assertAllFormatting(t, or(or(eq("a", 1), eq("b", 2)), eq("c", 3)), `a = ? OR b = ? OR c = ?`)
+
+	assertAllFormatting(t,
+		ast.NewUnaryExpr(or(eq("a", 1), eq("b", 2)), ast.UnaryIsNull),
+		`(a = ? OR b = ?) IS NULL`,
+	)
+
+	// The databases rank comparisons differently among themselves, so they're always parenthesized
+	// inside one another.
+	assertAllFormatting(t,
+		ast.NewBinaryExpr(eq("a", 1), ast.BinaryLess, ast.NewPlaceholderLiteral(2)),
+		`(a = ?) < ?`,
+	)
+	between := ast.NewTernaryExpr(ast.NewIdentifier("a"), ast.TernaryBetween, ast.NewPlaceholderLiteral(1), ast.NewPlaceholderLiteral(2))
+	assertAllFormatting(t,
+		ast.NewBinaryExpr(between, ast.BinaryEquals, ast.NewPlaceholderLiteral(false)),
+		`(a BETWEEN ? AND ?) = ?`,
+	)
+	assertAllFormatting(t,
+		ast.NewBinaryExpr(ast.NewUnaryExpr(ast.NewIdentifier("a"), ast.UnaryIsNull), ast.BinaryEquals, ast.NewPlaceholderLiteral(true)),
+		`(a IS NULL) = ?`,
+	)
+	assertAllFormatting(t,
+		ast.NewUnaryExpr(eq("a", 1), ast.UnaryIsNull),
+		`(a = ?) IS NULL`,
+	)
+}
+
+func TestBoolLiteral(t *testing.T) {
+	assertFormatting(t,
+		newFormatTestCase(Mysql{}, ast.NewBoolLiteral(true), `TRUE`),
+		newFormatTestCase(Mysql{}, ast.NewBoolLiteral(false), `FALSE`),
+		newFormatTestCase(Sqlite{}, ast.NewBoolLiteral(true), `1`),
+		newFormatTestCase(Sqlite{}, ast.NewBoolLiteral(false), `0`),
+	)
+}
+
+func TestRowValues(t *testing.T) {
+	cols := ast.NewTupleLiteral(ast.NewIdentifier("a"), ast.NewIdentifier("b"))
+	row := func(vs ...int) *ast.TupleLiteral {
+		var exprs []ast.IntoExpr
+		for _, v := range vs {
+			exprs = append(exprs, ast.NewPlaceholderLiteral(v))
+		}
+		return ast.NewTupleLiteral(exprs...)
+	}
+
+	assertAllFormatting(t, ast.NewBinaryExpr(cols, ast.BinaryGreater, row(1, 2)), `(a,b) > (?,?)`)
+	assertFormattingError(t, Mysql{}, ast.NewBinaryExpr(cols, ast.BinaryEquals, row(1, 2, 3)))
+	assertFormattingError(t, Sqlite{}, ast.NewBinaryExpr(cols, ast.BinaryEquals, row(1)))
+
+	in := ast.NewBinaryExpr(cols, ast.BinaryIn, ast.NewTupleLiteral(row(1, 2), row(3, 4)))
+	assertFormatting(t,
+		newFormatTestCase(Mysql{}, in, `(a,b) IN ((?,?),(?,?))`),
+		newFormatTestCase(Sqlite{}, in, `(a = ? AND b = ? OR a = ? AND b = ?)`),
+	)
+
+	notIn := ast.NewBinaryExpr(cols, ast.BinaryNotIn, ast.NewTupleLiteral(row(1, 2)))
+	assertFormatting(t,
+		newFormatTestCase(Mysql{}, notIn, `(a,b) NOT IN ((?,?))`),
+		newFormatTestCase(Sqlite{}, notIn, `(NOT (a = ? AND b = ?))`),
+	)
+
+	assertFormattingError(t, Mysql{}, ast.NewBinaryExpr(cols, ast.BinaryIn, ast.NewTupleLiteral(row(1, 2), row(3))))
+	assertFormattingError(t, Sqlite{}, ast.NewBinaryExpr(cols, ast.BinaryIn, ast.NewTupleLiteral(row(1, 2), row(3))))
+}
+
+func TestSubquery(t *testing.T) {
+	sub := ast.NewSelect(ast.NewTableName("bar"), ast.NewIdentifier("b"))
+	sub.WithWhere(ast.NewBinaryExpr(ast.NewIdentifier("c"), ast.BinaryGreater, ast.NewPlaceholderLiteral(1)))
+
+	assertAllFormatting(t,
+		ast.NewBinaryExpr(ast.NewIdentifier("a"), ast.BinaryIn, ast.NewSubquery(sub)),
+		`a IN (SELECT b FROM bar WHERE c > ?)`,
+	)
+}
+
+func TestBuiltins(t *testing.T) {
+	a, b := ast.NewIdentifier("a"), ast.NewIdentifier("b")
+
+	assertAllFormatting(t, ast.NewFunction("COALESCE", a, b), `COALESCE(a,b)`)
+
+	cases := []struct {
+		node   ast.Node
+		mysql  string
+		sqlite string
+	}{{
+		node:   ast.NewBuiltin(ast.BuiltinLength, a),
+		mysql:  `CHAR_LENGTH(a)`,
+		sqlite: `LENGTH(a)`,
+	}, {
+		node:   ast.NewBuiltin(ast.BuiltinConcat, a, ast.NewPlaceholderLiteral("-"), b),
+		mysql:  `CONCAT(a,?,b)`,
+		sqlite: `(a || ? || b)`,
+	}, {
+		node:   ast.NewBuiltin(ast.BuiltinNow),
+		mysql:  `NOW()`,
+		sqlite: `STRFTIME('%Y-%m-%dT%H:%M:%fZ','now')`,
+	}, {
+		node:   ast.NewBuiltin(ast.BuiltinExtract, a).WithUnit(ast.TimeUnitMonth),
+		mysql:  `EXTRACT(MONTH FROM a)`,
+		sqlite: `CAST(STRFTIME('%m',a) AS INTEGER)`,
+	}, {
+		node:   ast.NewBuiltin(ast.BuiltinTruncTime, a).WithUnit(ast.TimeUnitDay),
+		mysql:  `CAST(DATE_FORMAT(a,'%Y-%m-%d 00:00:00') AS DATETIME)`,
+		sqlite: `STRFTIME('%Y-%m-%dT00:00:00.000Z',a)`,
+	}}
+	for _, tc := range cases {
+		assertFormatting(t,
+			newFormatTestCase(Mysql{}, tc.node, tc.mysql),
+			newFormatTestCase(Sqlite{}, tc.node, tc.sqlite),
+		)
+	}
+
+	gc := ast.NewGroupConcat(a)
+	gc.Separator = "|"
+	gc.OrderBy = &ast.OrderBy{Orders: []ast.Order{ast.NewOrder(b, ast.OrderDesc)}}
+	assertFormatting(t,
+		newFormatTestCase(Mysql{}, gc, `GROUP_CONCAT(a ORDER BY b DESC SEPARATOR '|')`),
+		newFormatTestCase(Sqlite{}, gc, `GROUP_CONCAT(a,'|' ORDER BY b DESC)`),
+	)
+
+	gc.Distinct = true
+	assertFormatting(t, newFormatTestCase(Mysql{}, gc, `GROUP_CONCAT(DISTINCT a ORDER BY b DESC SEPARATOR '|')`))
+	assertFormattingError(t, Sqlite{}, gc)
+
+	gc.Separator = ","
+	assertFormatting(t, newFormatTestCase(Sqlite{}, gc, `GROUP_CONCAT(DISTINCT a ORDER BY b DESC)`))
+}
+
+func TestCase(t *testing.T) {
+	a := ast.NewIdentifier("a")
+	ph := ast.NewPlaceholderLiteral
+
+	searched := ast.NewCase(nil).
+		AddWhen(ast.NewBinaryExpr(a, ast.BinaryGreater, ph(1)), ph("big")).
+		AddWhen(ast.NewUnaryExpr(a, ast.UnaryIsNull), ph("none")).
+		WithElse(ph("small"))
+	assertAllFormatting(t, searched, `CASE WHEN a > ? THEN ? WHEN a IS NULL THEN ? ELSE ? END`)
+
+	simple := ast.NewCase(a).AddWhen(ph(1), ast.NewIntegerLiteral(0))
+	assertAllFormatting(t, simple, `CASE a WHEN ? THEN 0 END`)
+
+	assertFormattingError(t, Mysql{}, ast.NewCase(a))
+	assertFormattingError(t, Sqlite{}, ast.NewCase(a))
+}
+
+func TestWindowFunctions(t *testing.T) {
+	spec := &ast.WindowSpec{
+		PartitionBy: []ast.Expr{ast.NewIdentifier("a"), ast.NewIdentifier("b")},
+		OrderBy:     &ast.OrderBy{Orders: []ast.Order{ast.NewOrder(ast.NewIdentifier("c"), ast.OrderDesc)}},
+		Frame: &ast.WindowFrame{
+			Unit:  ast.FrameRows,
+			Start: ast.FrameBound{Kind: ast.BoundPreceding, Offset: 2},
+			End:   ast.FrameBound{Kind: ast.BoundCurrentRow},
+		},
+	}
+	fn := ast.NewWindowFunc(ast.NewFunction("ROW_NUMBER"), spec)
+	exp := `ROW_NUMBER() OVER (PARTITION BY a,b ORDER BY c DESC ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)`
+
+	assertFormatting(t,
+		newFormatTestCase(Mysql{Version: MySQL80}, fn, exp),
+		newFormatTestCase(Sqlite{}, fn, exp),
+	)
+	assertFormattingError(t, Mysql{}, fn)
+
+	sel := ast.NewSelect(ast.NewTableName("foo"), ast.NewNamedWindowFunc(ast.NewFunction("RANK"), "w"))
+	sel.WithWindow("w", &ast.WindowSpec{
+		OrderBy: &ast.OrderBy{Orders: []ast.Order{ast.NewOrder(ast.NewIdentifier("c"), ast.OrderAsc)}},
+		Frame: &ast.WindowFrame{
+			Unit:  ast.FrameRange,
+			Start: ast.FrameBound{Kind: ast.BoundUnboundedPreceding},
+			End:   ast.FrameBound{Kind: ast.BoundUnboundedFollowing},
+		},
+	})
+	exp = `SELECT RANK() OVER w FROM foo WINDOW w AS (ORDER BY c ASC RANGE BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING)`
+
+	assertFormatting(t,
+		newFormatTestCase(Mysql{Version: MySQL80}, sel, exp),
+		newFormatTestCase(Sqlite{}, sel, exp),
+	)
+	assertFormattingError(t, Mysql{}, sel)
+}
+
+func TestGroupBy(t *testing.T) {
+	sel := ast.NewSelect(
+		ast.NewTableName("foo"),
+		ast.NewIdentifier("a"),
+		ast.NewAlias(ast.NewFunction("COUNT", ast.NewStarLiteral()), "n"),
+	)
+	sel.WithGroupBy(ast.NewIdentifier("a"), ast.NewIdentifier("b"))
+	sel.WithHaving(ast.NewBinaryExpr(ast.NewIdentifier("n"), ast.BinaryGreater, ast.NewPlaceholderLiteral(1)))
+	sel.WithOrders(ast.NewOrder(ast.NewIdentifier("n"), ast.OrderDesc))
+
+	assertAllFormatting(t, sel, `SELECT a,COUNT(*) AS n FROM foo GROUP BY a,b HAVING n > ? ORDER BY n DESC`)
+}
+
+func TestUpdateOrderByLimit(t *testing.T) {
+	u := ast.NewUpdate(ast.NewTableName("foo")).
+		WithOrders(ast.NewOrder(ast.NewIdentifier("a"), ast.OrderDesc)).
+		WithLimit(ast.None(), ast.NewIntegerLiteral(10))
+	u.AddAssignments(ast.NewBinaryExpr(ast.NewIdentifier("b"), ast.BinaryEquals, ast.NewPlaceholderLiteral(1)))
+
+	assertAllFormatting(t, u, `UPDATE foo SET b = ? ORDER BY a DESC LIMIT 10`)
+}
+
+func TestLock(t *testing.T) {
+	sel := func(k ast.LockKind) *ast.Select {
+		return ast.NewSelect(ast.NewTableName("foo"), ast.NewIdentifier("a")).WithLock(k)
+	}
+
+	assertFormatting(
+		t,
+		newFormatTestCase(Mysql{}, sel(ast.NoLock), `SELECT a FROM foo`),
+		newFormatTestCase(Mysql{}, sel(ast.SharedLock), `SELECT a FROM foo FOR SHARE`),
+		newFormatTestCase(Mysql{}, sel(ast.ForUpdateLock), `SELECT a FROM foo FOR UPDATE`),
+		newFormatTestCase(Sqlite{}, sel(ast.NoLock), `SELECT a FROM foo`),
+		newFormatTestCase(Sqlite{}, sel(ast.ForUpdateLock), `SELECT a FROM foo`),
+	)
+}
+
+func TestRaw(t *testing.T) {
+	raw := ast.NewRaw(`a = ? OR b = '?'`, 1)
+	assertAllFormatting(t, raw, `a = ? OR b = '?'`)
+	assertAllFormatting(t,
+		ast.NewBinaryExpr(raw, ast.BinaryAnd, ast.NewIdentifier("c")),
+		`(a = ? OR b = '?') AND c`,
+	)
+
+	assertFormattingError(t, Mysql{}, ast.NewRaw(`a = ?`))
+	assertFormattingError(t, Sqlite{}, ast.NewRaw(`a = ?`, 1, 2))
+
+	// Markers in comments don't count.
+	assertAllFormatting(t, ast.NewRaw("/* ? */ a = ? -- b = ?\n", 1), "/* ? */ a = ? -- b = ?\n")
+
+	// MySQL strings have backslash escapes, SQLite's don't.
+	escaped := ast.NewRaw(`'a\'' = ?`, 1)
+	assertFormatting(t, newFormatTestCase(Mysql{}, escaped, `'a\'' = ?`))
+	assertFormattingError(t, Sqlite{}, escaped)
+	assertFormatting(t, newFormatTestCase(Sqlite{}, ast.NewRaw(`'a\' = ?`, 1), `'a\' = ?`))
+}
+
+type unknownNode struct{}
+
+func (n unknownNode) AcceptVisitor(fn func(ast.Node) bool) {
+	fn(n)
+}
+
+func TestValidate(t *testing.T) {
+	tbl := ast.NewTableName("t")
+	a := ast.NewIdentifier("a")
+	createTable := func(cs *ast.ColumnSpec) *ast.CreateTable {
+		ct := ast.NewCreateTable("t")
+		ct.AddColumn(cs)
+		return ct
+	}
+
+	tests := []struct {
+		node   ast.Node
+		clause string
+	}{{
+		node:   ast.NewSelect(tbl),
+		clause: `SELECT`,
+	}, {
+		node:   ast.NewSelect(tbl, ast.NewDistinct()),
+		clause: `DISTINCT`,
+	}, {
+		node:   ast.NewUpdate(tbl),
+		clause: `SET`,
+	}, {
+		node:   ast.NewInsert(tbl),
+		clause: `INSERT`,
+	}, {
+		node:   ast.NewInsert(tbl, a),
+		clause: `VALUES`,
+	}, {
+		node:   ast.NewCreateTable("t"),
+		clause: `CREATE TABLE`,
+	}, {
+		// Problems nested in expressions are found too.
+		node:   ast.NewDelete(tbl).WithWhere(ast.NewBinaryExpr(a, ast.BinaryEquals, ast.NewCase(nil))),
+		clause: `CASE`,
+	}, {
+		// Only IN and NOT IN treat an empty list as a constant.
+		node:   ast.NewSelect(tbl, a).WithWhere(filter.ExprEquals(a, ast.NewTupleLiteral())),
+		clause: `row value`,
+	}, {
+		// DDL can't bind arguments.
+		node:   createTable(ast.NewColumnSpec("a", ast.BigInt()).WithDefault(ast.NewPlaceholderLiteral(5))),
+		clause: `DEFAULT`,
+	}, {
+		node:   createTable(ast.NewColumnSpec("a", ast.BigInt()).WithGenerated(ast.NewPlaceholderLiteral(6), true)),
+		clause: `GENERATED`,
+	}, {
+		// Names which are written into DDL unquoted.
+		node:   createTable(ast.NewColumnSpec("a", ast.VarChar(32)).WithCollation("NOCASE; DROP TABLE t")),
+		clause: `COLLATE`,
+	}, {
+		node: func() ast.Node {
+			ct := createTable(ast.NewColumnSpec("a", ast.BigInt()))
+			ct.AddOption(ast.NewTableOption(ast.TableOptionEngine, "InnoDB "))
+			return ct
+		}(),
+		clause: `CREATE TABLE`,
+	}, {
+		// Out of range units and directions.
+		node:   ast.NewSelect(tbl, functions.TruncTime(functions.TimeUnit(42), a)),
+		clause: `TRUNC`,
+	}, {
+		node:   ast.NewSelect(tbl, a).WithOrders(ast.NewOrder(a, filter.Direction(7).ToASTDirection())),
+		clause: `ORDER BY`,
+	}, {
+		node:   unknownNode{},
+		clause: ``,
+	}}
+
+	for _, tc := range tests {
+		for _, f := range []formatter{Mysql{}, Sqlite{}} {
+			sb := &strings.Builder{}
+			err := f.FormatNode(sb, tc.node)
+
+			var be *ast.BuildError
+			assert.Equal(t, errors.As(err, &be), true)
+			assert.Equal(t, be.Clause, tc.clause)
+			assert.Equal(t, sb.String(), ``)
+		}
+	}
+
+	ins := ast.NewInsert(tbl, a, ast.NewIdentifier("b"))
+	ins.AddValues(ast.NewPlaceholderLiteral(1))
+	err := Mysql{}.FormatNode(io.Discard, ins)
+	assert.Equal(t, err.Error(), `VALUES: 1 values for 2 columns`)
+}
+
+type valuer struct{ s string }
+
+func (v valuer) Value() (driver.Value, error) { return v.s, nil }
+
+func TestInterpolate(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	stmt := statement.Statement{
+		Stmt: `SELECT * FROM T WHERE a = ? AND b = '?' AND c IN (?,?,?,?,?,?,?,?)`,
+		Args: []any{`it's`, nil, true, 12, 1.5, []byte{0xde, 0xad}, ts, valuer{`v`}, (*int)(nil)},
+	}
+
+	res, err := stmt.Interpolate(Mysql{})
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`SELECT * FROM T WHERE a = 'it''s' AND b = '?' AND c IN (NULL,TRUE,12,1.5,X'dead','2024-01-02 03:04:05.000006','v',NULL)`,
+		res,
+	)
+
+	res, err = stmt.Interpolate(Sqlite{})
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`SELECT * FROM T WHERE a = 'it''s' AND b = '?' AND c IN (NULL,1,12,1.5,X'dead','2024-01-02T03:04:05.000Z','v',NULL)`,
+		res,
+	)
+
+	// SQLite times are bound the same way they're interpolated.
+	assert.Equal(t, Sqlite{}.ConvertArg(ts.In(time.FixedZone("", 3600))), any(`2024-01-02T03:04:05.000Z`))
+	assert.Equal(t, Pretty{Formatter: Sqlite{}}.ConvertArg(ts), any(`2024-01-02T03:04:05.000Z`))
+	assert.Equal(t, Sqlite{}.ConvertArg(12), any(12))
+
+	res, err = statement.Statement{Stmt: `a = ?`, Args: []any{`back\\slash`}}.Interpolate(Mysql{})
+	assert.NoError(t, err)
+	assert.Equal(t, `a = 'back\\\\slash'`, res)
+
+	_, err = statement.Statement{Stmt: `a = ?`}.Interpolate(Mysql{})
+	assert.Error(t, err)
+
+	// Placeholders are found the way the dialect reads strings and comments.
+	res, err = statement.Statement{Stmt: `a = 'x\'?' AND b = ? -- c = ?`, Args: []any{1}}.Interpolate(Mysql{})
+	assert.NoError(t, err)
+	assert.Equal(t, res, `a = 'x\'?' AND b = 1 -- c = ?`)
+
+	_, err = statement.Statement{Stmt: `a = ?`, Args: []any{struct{}{}}}.Interpolate(Sqlite{})
+	assert.Error(t, err)
+}
+
+func TestPretty(t *testing.T) {
+	id := ast.NewIdentifier
+	eq := func(col string, v any) ast.IntoExpr {
+		return ast.NewBinaryExpr(id(col), ast.BinaryEquals, ast.NewPlaceholderLiteral(v))
+	}
+
+	sub := ast.NewSelect(ast.NewTableName("bar"), ast.NewFunction("MAX", id("x")))
+	sub.WithWhere(ast.NewBinaryExpr(eq("y", 1), ast.BinaryAnd, eq("z", 2)))
+
+	sel := ast.NewSelect(
+		ast.NewJoin(
+			ast.JoinKindInner,
+			ast.NewTableName("foo"),
+			ast.NewTableName("bar"),
+			ast.NewBinaryExpr(
+				ast.NewBinaryExpr(id("foo.a"), ast.BinaryEquals, id("bar.a")),
+				ast.BinaryAnd,
+				eq("bar.b", 3),
+			),
+		),
+		id("a_long_column_name"),
+		id("another_long_column_name"),
+		ast.NewAlias(ast.NewSubquery(sub), "m"),
+	)
+	sel.WithWhere(ast.NewBinaryExpr(
+		eq("a", `it's AND (`),
+		ast.BinaryAnd,
+		ast.NewBinaryExpr(eq("b", 4), ast.BinaryOr, ast.NewTernaryExpr(id("c"), ast.TernaryBetween, ast.NewPlaceholderLiteral(5), ast.NewPlaceholderLiteral(6))),
+	))
+	sel.WithGroupBy(id("a"), id("b"))
+	sel.WithOrders(ast.NewOrder(id("a"), ast.OrderDesc))
+
+	exp := `SELECT
+  a_long_column_name,
+  another_long_column_name,
+  (
+    SELECT MAX(x)
+    FROM bar
+    WHERE y = ?
+      AND z = ?
+  ) AS m
+FROM foo
+  INNER JOIN bar ON foo.a = bar.a
+    AND bar.b = ?
+WHERE a = ?
+  AND (
+    b = ?
+    OR c BETWEEN ? AND ?
+  )
+GROUP BY a,b
+ORDER BY a DESC`
+	assertFormatting(t,
+		newFormatTestCase(Pretty{Formatter: Mysql{}}, sel, exp),
+		newFormatTestCase(Pretty{Formatter: Sqlite{}}, sel, exp),
+	)
+
+	// Short lists stay on one line.
+	short := ast.NewSelect(ast.NewTableName("foo"), id("a"), id("b"))
+	short.WithWhere(eq("a", 1))
+	assertFormatting(t,
+		newFormatTestCase(Pretty{Formatter: Mysql{}}, short, "SELECT a,b\nFROM foo\nWHERE a = ?"),
+		newFormatTestCase(Pretty{Formatter: Mysql{}, Width: 8}, short, "SELECT\n  a,\n  b\nFROM foo\nWHERE a = ?"),
+	)
+
+	// Pretty only changes whitespace, so arguments are in the same order.
+	assert.Equal(t, ast.GetArgs(sel), []any{1, 2, 3, `it's AND (`, 4, 5, 6})
+
+	assertFormattingError(t, Pretty{Formatter: Sqlite{}}, ast.NewRaw(`a = ?`))
+
+	// Line comments keep their line break, so they don't comment out the rest of the statement. Block
+	// comments are kept intact.
+	commented := ast.NewSelect(ast.NewTableName("foo"), ast.NewRaw("x -- it's\n + 1"), ast.NewRaw("y--z\n"))
+	commented.WithWhere(ast.NewRaw("/* (',' */ a = ? # or\n OR b", 1))
+	assertFormatting(t,
+		newFormatTestCase(Pretty{Formatter: Mysql{}}, commented, "SELECT\n  x -- it's\n  + 1,\n  y--z\nFROM foo\nWHERE /* (',' */ a = ? # or\n  OR b"),
+	)
+
+	// Strings are read the way the wrapped formatter's dialect reads them.
+	escaped := ast.NewSelect(ast.NewTableName("foo"), ast.NewIdentifier("a")).
+		WithWhere(ast.NewRaw(`b = 'it\'s (' AND c = ?`, 1))
+	assertFormatting(t,
+		newFormatTestCase(Pretty{Formatter: Mysql{}}, escaped, "SELECT a\nFROM foo\nWHERE b = 'it\\'s ('\n  AND c = ?"),
+	)
+}
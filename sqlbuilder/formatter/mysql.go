@@ -1,15 +1,94 @@
 package formatter
 
 import (
+	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/scan"
 )
 
-type Mysql struct{}
+// mysqlTimeFormat is the format MySQL accepts for DATETIME and TIMESTAMP literals. Like the MySQL
+// driver, we render times in UTC since MySQL doesn't store a time zone.
+const mysqlTimeFormat = `2006-01-02 15:04:05.999999`
 
-func (m Mysql) FormatNode(w io.Writer, n ast.Node) {
+// MysqlVersion is a MySQL server version, for syntax which only newer versions support.
+type MysqlVersion int
+
+const (
+	MySQL57 MysqlVersion = iota
+	MySQL80
+)
+
+func (v MysqlVersion) String() string {
+	switch v {
+	case MySQL57:
+		return `5.7`
+	case MySQL80:
+		return `8.0`
+	}
+	return `unknown`
+}
+
+type Mysql struct {
+	// Version is the oldest server version statements must run on. It defaults to MySQL57, so syntax
+	// which needs a newer version (e.g. window functions) is rejected unless this is raised.
+	Version MysqlVersion
+}
+
+func (m Mysql) FormatNode(w io.Writer, n ast.Node) (err error) {
+	if err := ast.Validate(n); err != nil {
+		return err
+	}
+
+	defer recoverFormatError(&err)
+	m.format(w, n)
+	return nil
+}
+
+// requireVersion fails unless the formatter targets at least version v.
+func (m Mysql) requireVersion(clause string, n ast.Node, v MysqlVersion, feature string) {
+	if m.Version < v {
+		failf(clause, n, `%s require MySQL %s or later, but the formatter targets MySQL %s`, feature, v, m.Version)
+	}
+}
+
+// ScanDialect describes how MySQL quotes strings and identifiers and writes comments, so that
+// Pretty and statement.Interpolate can find their way around its SQL.
+func (m Mysql) ScanDialect() scan.Dialect {
+	return scan.Mysql
+}
+
+// Literal renders an argument as a Mysql literal, for statement.Statement.Interpolate.
+func (m Mysql) Literal(v driver.Value) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return `NULL`, nil
+	case bool:
+		if v {
+			return `TRUE`, nil
+		}
+		return `FALSE`, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		return m.quoteString(v), nil
+	case []byte:
+		return `X'` + hex.EncodeToString(v) + `'`, nil
+	case time.Time:
+		return m.quoteString(v.UTC().Format(mysqlTimeFormat)), nil
+	}
+	return ``, fmt.Errorf(`cannot render %T as a literal`, v)
+}
+
+func (m Mysql) format(w io.Writer, n ast.Node) {
 	switch tn := n.(type) {
 	case *ast.Select:
 		m.formatSelect(w, tn)
@@ -21,18 +100,26 @@ func (m Mysql) FormatNode(w io.Writer, n ast.Node) {
 		m.formatUpdate(w, tn)
 	case *ast.CreateTable:
 		m.formatCreateTable(w, tn)
+	case *ast.CreateView:
+		m.formatCreateView(w, tn)
+	case *ast.DropView:
+		m.formatDropView(w, tn)
 	case *ast.ColumnSpec:
 		m.formatColumnSpec(w, tn)
 	case ast.ColumnType:
 		m.formatColumnType(w, tn)
 	case *ast.ColumnDefault:
 		m.formatColumnDefault(w, tn)
+	case *ast.GeneratedColumn:
+		m.formatGeneratedColumn(w, tn)
 	case ast.Nullability:
 		m.formatNullability(w, tn)
 	case *ast.AutoIncrement:
 		m.formatAutoIncrement(w, tn)
 	case *ast.PrimaryKey:
 		m.formatPrimaryKey(w, tn)
+	case *ast.TableOption:
+		m.formatTableOption(w, tn)
 	case *ast.TableName:
 		m.formatTableName(w, tn)
 	case *ast.Join:
@@ -57,6 +144,8 @@ func (m Mysql) FormatNode(w io.Writer, n ast.Node) {
 		m.formatUnaryExpr(w, tn)
 	case *ast.BinaryExpr:
 		m.formatBinaryExpr(w, tn)
+	case *ast.TernaryExpr:
+		m.formatTernaryExpr(w, tn)
 	case *ast.PlaceholderLiteral:
 		m.formatPlaceholderLiteral(w, tn)
 	case *ast.TupleLiteral:
@@ -67,10 +156,32 @@ func (m Mysql) FormatNode(w io.Writer, n ast.Node) {
 		m.formatStringLiteral(w, tn)
 	case *ast.NullLiteral:
 		m.formatNullLiteral(w, tn)
+	case *ast.BoolLiteral:
+		m.formatBoolLiteral(w, tn)
+	case *ast.TimeLiteral:
+		m.formatTimeLiteral(w, tn)
+	case *ast.CurrentTimestampLiteral:
+		fmt.Fprint(w, `CURRENT_TIMESTAMP`)
 	case *ast.OrderBy:
 		m.formatOrderBy(w, tn)
 	case *ast.Function:
 		m.formatFunction(w, tn)
+	case *ast.Case:
+		m.formatCase(w, tn)
+	case *ast.Raw:
+		m.formatRaw(w, tn)
+	case *ast.WindowFunc:
+		m.formatWindowFunc(w, tn)
+	case *ast.WindowSpec:
+		m.formatWindowSpec(w, tn)
+	case *ast.Builtin:
+		m.formatBuiltin(w, tn)
+	case *ast.GroupConcat:
+		m.formatGroupConcat(w, tn)
+	case *ast.Subquery:
+		fmt.Fprint(w, `(`)
+		m.formatSelect(w, tn.Select)
+		fmt.Fprint(w, `)`)
 	case *ast.StarLiteral:
 		fmt.Fprint(w, "*")
 	case *ast.Distinct:
@@ -78,13 +189,13 @@ func (m Mysql) FormatNode(w io.Writer, n ast.Node) {
 	case *ast.OnDuplicateKey:
 		m.formatOnDuplicateKey(w, tn)
 	default:
-		panic(fmt.Sprintf(`unexpected node: %T`, n))
+		failf(``, n, `unexpected node: %T`, n)
 	}
 }
 
-func formatCommaDelimited[T ast.Node](w io.Writer, f interface{ FormatNode(w io.Writer, n ast.Node) }, ns ...T) {
+func formatCommaDelimited[T ast.Node](w io.Writer, f interface{ format(w io.Writer, n ast.Node) }, ns ...T) {
 	for i, n := range ns {
-		f.FormatNode(w, n)
+		f.format(w, n)
 		if i < len(ns)-1 {
 			fmt.Fprint(w, `,`)
 		}
@@ -96,76 +207,94 @@ func (m Mysql) formatSelect(w io.Writer, s *ast.Select) {
 	formatCommaDelimited(w, m, s.Exprs...)
 
 	fmt.Fprint(w, ` FROM `)
-	m.FormatNode(w, s.From)
+	m.format(w, s.From)
 
 	if s.Where != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, s.Where)
+		m.format(w, s.Where)
+	}
+	if s.GroupBy != nil {
+		fmt.Fprint(w, ` GROUP BY `)
+		formatCommaDelimited(w, m, s.GroupBy.Exprs...)
+	}
+	if s.Having != nil {
+		fmt.Fprint(w, ` HAVING `)
+		m.format(w, s.Having.Expr)
+	}
+	if len(s.Windows) > 0 {
+		m.requireVersion(`WINDOW`, s, MySQL80, `window functions`)
+		fmt.Fprint(w, ` WINDOW `)
+		for i, win := range s.Windows {
+			if i > 0 {
+				fmt.Fprint(w, `,`)
+			}
+			fmt.Fprintf(w, `%s AS (`, win.Name)
+			m.format(w, win.Spec)
+			fmt.Fprint(w, `)`)
+		}
 	}
 	if s.OrderBy != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, s.OrderBy)
+		m.format(w, s.OrderBy)
 	}
 	if s.Limit != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, s.Limit)
+		m.format(w, s.Limit)
 	}
-	if s.Lock != nil {
+	if s.Lock != nil && s.Lock.Kind != ast.NoLock {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, s.Lock)
+		m.format(w, s.Lock)
 	}
 }
 
 func (m Mysql) formatDelete(w io.Writer, d *ast.Delete) {
 	fmt.Fprint(w, `DELETE FROM `)
-	m.FormatNode(w, d.From)
+	m.format(w, d.From)
 
 	if d.Where != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, d.Where)
+		m.format(w, d.Where)
 	}
 	if d.OrderBy != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, d.OrderBy)
+		m.format(w, d.OrderBy)
 	}
 	if d.Limit != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, d.Limit)
+		m.format(w, d.Limit)
 	}
 }
 
 func (m Mysql) formatInsert(w io.Writer, i *ast.Insert) {
 	fmt.Fprint(w, `INSERT INTO `)
-	m.FormatNode(w, i.Into)
+	m.format(w, i.Into)
 	fmt.Fprint(w, ` (`)
 	formatCommaDelimited(w, m, i.Columns...)
 	fmt.Fprint(w, `) VALUES `)
 	formatCommaDelimited(w, m, i.Values...)
 	if i.OnDuplicateKey != nil {
-		m.FormatNode(w, i.OnDuplicateKey)
+		m.format(w, i.OnDuplicateKey)
 	}
 }
 
 func (m Mysql) formatUpdate(w io.Writer, u *ast.Update) {
 	fmt.Fprint(w, `UPDATE `)
-	m.FormatNode(w, u.Table)
+	m.format(w, u.Table)
 	fmt.Fprint(w, ` SET `)
 	formatCommaDelimited(w, m, u.AssignmentList...)
 
 	if u.Where != nil {
 		fmt.Fprintf(w, ` `)
-		m.FormatNode(w, u.Where)
+		m.format(w, u.Where)
 	}
 
-	// TODO we "support" these in the formatter, but we don't expose them to the public via the builders.
-	// Add tests for these once we support them publicly.
 	if u.OrderBy != nil {
-		fmt.Fprint(w, ` ORDER BY `)
-		m.FormatNode(w, u.OrderBy)
+		fmt.Fprint(w, ` `)
+		m.format(w, u.OrderBy)
 	}
 	if u.Limit != nil {
-		fmt.Fprint(w, ` LIMIT `)
-		m.FormatNode(w, u.Limit)
+		fmt.Fprint(w, ` `)
+		m.format(w, u.Limit)
 	}
 }
 
@@ -175,34 +304,112 @@ func (m Mysql) formatCreateTable(w io.Writer, ct *ast.CreateTable) {
 		fmt.Fprint(w, `IF NOT EXISTS `)
 	}
 
-	m.FormatNode(w, ct.Name)
+	m.format(w, ct.Name)
 
-	fmt.Fprint(w, `(`)
-	formatCommaDelimited(w, m, ct.Columns...)
+	if ct.Like != nil {
+		fmt.Fprint(w, ` LIKE `)
+		m.format(w, ct.Like)
+		return
+	}
+
+	// The column list is optional when creating a table from a SELECT.
+	if ct.AsSelect == nil || len(ct.Columns) > 0 {
+		fmt.Fprint(w, `(`)
+		formatCommaDelimited(w, m, ct.Columns...)
 
-	if ct.PrimaryKey != nil {
-		fmt.Fprint(w, `,`)
-		m.FormatNode(w, ct.PrimaryKey)
+		if ct.PrimaryKey != nil {
+			fmt.Fprint(w, `,`)
+			m.format(w, ct.PrimaryKey)
+		}
+
+		fmt.Fprint(w, `)`)
 	}
 
-	fmt.Fprint(w, `)`)
+	for _, opt := range ct.Options {
+		fmt.Fprint(w, ` `)
+		m.format(w, opt)
+	}
+
+	if ct.AsSelect != nil {
+		fmt.Fprint(w, ` AS `)
+		m.format(w, ct.AsSelect)
+	}
+}
+
+func (m Mysql) formatTableOption(w io.Writer, opt *ast.TableOption) {
+	switch opt.Kind {
+	case ast.TableOptionEngine:
+		fmt.Fprintf(w, `ENGINE=%s`, opt.Value)
+	case ast.TableOptionCharset:
+		fmt.Fprintf(w, `DEFAULT CHARSET=%s`, opt.Value)
+	case ast.TableOptionCollate:
+		fmt.Fprintf(w, `COLLATE=%s`, opt.Value)
+	case ast.TableOptionComment:
+		fmt.Fprintf(w, `COMMENT=%s`, m.quoteString(opt.Value))
+	default:
+		failf(`CREATE TABLE`, opt, `MySQL does not support table option %s`, opt.Kind)
+	}
+}
+
+func (m Mysql) formatCreateView(w io.Writer, cv *ast.CreateView) {
+	fmt.Fprint(w, `CREATE `)
+	if cv.OrReplace {
+		fmt.Fprint(w, `OR REPLACE `)
+	}
+	fmt.Fprint(w, `VIEW `)
+	if cv.IfNotExists {
+		failf(`CREATE VIEW`, cv, `MySQL does not support CREATE VIEW IF NOT EXISTS`)
+	}
+
+	m.format(w, cv.Name)
+
+	if len(cv.Columns) > 0 {
+		fmt.Fprint(w, `(`)
+		formatCommaDelimited(w, m, cv.Columns...)
+		fmt.Fprint(w, `)`)
+	}
+
+	fmt.Fprint(w, ` AS `)
+	m.format(w, cv.As)
+}
+
+func (m Mysql) formatDropView(w io.Writer, dv *ast.DropView) {
+	fmt.Fprint(w, `DROP VIEW `)
+	if dv.IfExists {
+		fmt.Fprint(w, `IF EXISTS `)
+	}
+	m.format(w, dv.Name)
 }
 
 func (m Mysql) formatColumnSpec(w io.Writer, cs *ast.ColumnSpec) {
-	m.FormatNode(w, cs.Name)
+	m.format(w, cs.Name)
 	fmt.Fprint(w, ` `)
-	m.FormatNode(w, cs.Type)
+	m.format(w, cs.Type)
+	if cs.Collation != `` {
+		fmt.Fprintf(w, ` COLLATE %s`, cs.Collation)
+	}
+	if cs.Generated != nil {
+		fmt.Fprint(w, ` `)
+		m.format(w, cs.Generated)
+	}
 	if cs.Nullability != ast.NoNullability {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, cs.Nullability)
+		m.format(w, cs.Nullability)
 	}
 	if cs.Default != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, cs.Default)
+		m.format(w, cs.Default)
+	}
+	if cs.OnUpdate != nil {
+		fmt.Fprint(w, ` ON UPDATE `)
+		m.format(w, cs.OnUpdate)
 	}
 	if cs.AutoIncrementing != nil {
 		fmt.Fprint(w, ` `)
-		m.FormatNode(w, cs.AutoIncrementing)
+		m.format(w, cs.AutoIncrementing)
+	}
+	if cs.Comment != `` {
+		fmt.Fprintf(w, ` COMMENT %s`, m.quoteString(cs.Comment))
 	}
 }
 
@@ -218,15 +425,15 @@ func (m Mysql) formatColumnType(w io.Writer, ct ast.ColumnType) {
 		fmt.Fprint(w, `BIGINT`)
 	case ast.CharColumn:
 		fmt.Fprint(w, `CHAR(`)
-		m.FormatNode(w, ast.NewIntegerLiteral(t.Size))
+		m.format(w, ast.NewIntegerLiteral(t.Size))
 		fmt.Fprint(w, `)`)
 	case ast.VarCharColumn:
 		fmt.Fprint(w, `VARCHAR(`)
-		m.FormatNode(w, ast.NewIntegerLiteral(t.Size))
+		m.format(w, ast.NewIntegerLiteral(t.Size))
 		fmt.Fprint(w, `)`)
 	case ast.TextColumn:
 		fmt.Fprint(w, `TEXT(`)
-		m.FormatNode(w, ast.NewIntegerLiteral(t.Size))
+		m.format(w, ast.NewIntegerLiteral(t.Size))
 		fmt.Fprint(w, `)`)
 	case ast.TinyBlobColumn:
 		fmt.Fprint(w, `TINYBLOB`)
@@ -238,12 +445,32 @@ func (m Mysql) formatColumnType(w io.Writer, ct ast.ColumnType) {
 		fmt.Fprint(w, `LONGBLOB`)
 	case ast.DateTimeColumn:
 		fmt.Fprint(w, `DATETIME`)
+	case ast.TimestampColumn:
+		fmt.Fprint(w, `TIMESTAMP`)
 	}
 }
 
 func (m Mysql) formatColumnDefault(w io.Writer, cd *ast.ColumnDefault) {
 	fmt.Fprint(w, `DEFAULT `)
-	m.FormatNode(w, cd.Value)
+	if cd.IsLiteral() {
+		m.format(w, cd.Value)
+		return
+	}
+
+	fmt.Fprint(w, `(`)
+	m.format(w, cd.Value)
+	fmt.Fprint(w, `)`)
+}
+
+func (m Mysql) formatGeneratedColumn(w io.Writer, g *ast.GeneratedColumn) {
+	fmt.Fprint(w, `GENERATED ALWAYS AS (`)
+	m.format(w, g.Expr)
+	fmt.Fprint(w, `)`)
+	if g.Stored {
+		fmt.Fprint(w, ` STORED`)
+	} else {
+		fmt.Fprint(w, ` VIRTUAL`)
+	}
 }
 
 func (m Mysql) formatNullability(w io.Writer, n ast.Nullability) {
@@ -272,18 +499,155 @@ func (m Mysql) formatFunction(w io.Writer, f *ast.Function) {
 	fmt.Fprint(w, `)`)
 }
 
+// mysqlTruncFormats are DATE_FORMAT patterns which zero every part of a time below the unit.
+var mysqlTruncFormats = map[ast.TimeUnit]string{
+	ast.TimeUnitYear:   `%Y-01-01 00:00:00`,
+	ast.TimeUnitMonth:  `%Y-%m-01 00:00:00`,
+	ast.TimeUnitDay:    `%Y-%m-%d 00:00:00`,
+	ast.TimeUnitHour:   `%Y-%m-%d %H:00:00`,
+	ast.TimeUnitMinute: `%Y-%m-%d %H:%i:00`,
+	ast.TimeUnitSecond: `%Y-%m-%d %H:%i:%s`,
+}
+
+func (m Mysql) formatBuiltin(w io.Writer, b *ast.Builtin) {
+	switch b.Func {
+	case ast.BuiltinLength:
+		// LENGTH counts bytes in MySQL.
+		fmt.Fprint(w, `CHAR_LENGTH(`)
+		formatCommaDelimited(w, m, b.Args...)
+		fmt.Fprint(w, `)`)
+	case ast.BuiltinConcat:
+		fmt.Fprint(w, `CONCAT(`)
+		formatCommaDelimited(w, m, b.Args...)
+		fmt.Fprint(w, `)`)
+	case ast.BuiltinNow:
+		fmt.Fprint(w, `NOW()`)
+	case ast.BuiltinExtract:
+		fmt.Fprintf(w, `EXTRACT(%s FROM `, b.Unit)
+		formatCommaDelimited(w, m, b.Args...)
+		fmt.Fprint(w, `)`)
+	case ast.BuiltinTruncTime:
+		layout, ok := mysqlTruncFormats[b.Unit]
+		if !ok {
+			failf(``, b, `cannot truncate a time to unit %v`, b.Unit)
+		}
+		fmt.Fprint(w, `CAST(DATE_FORMAT(`)
+		formatCommaDelimited(w, m, b.Args...)
+		fmt.Fprintf(w, `,%s) AS DATETIME)`, m.quoteString(layout))
+	default:
+		failf(``, b, `unsupported function: %v`, b.Func)
+	}
+}
+
+func (m Mysql) formatGroupConcat(w io.Writer, g *ast.GroupConcat) {
+	fmt.Fprint(w, `GROUP_CONCAT(`)
+	if g.Distinct {
+		fmt.Fprint(w, `DISTINCT `)
+	}
+	m.format(w, g.Arg)
+	if g.OrderBy != nil {
+		fmt.Fprint(w, ` `)
+		m.format(w, g.OrderBy)
+	}
+	fmt.Fprintf(w, ` SEPARATOR %s)`, m.quoteString(g.Separator))
+}
+
+func (m Mysql) formatRaw(w io.Writer, raw *ast.Raw) {
+	frags := raw.Fragments(scan.Mysql)
+	if len(frags)-1 != len(raw.Args) {
+		failf(`raw SQL`, raw, `%d placeholders but %d arguments`, len(frags)-1, len(raw.Args))
+	}
+	for i, frag := range frags {
+		fmt.Fprint(w, frag)
+		if i < len(raw.Args) {
+			m.format(w, raw.Args[i])
+		}
+	}
+}
+
+func (m Mysql) formatCase(w io.Writer, c *ast.Case) {
+	fmt.Fprint(w, `CASE`)
+	if c.Operand != nil {
+		fmt.Fprint(w, ` `)
+		m.format(w, c.Operand)
+	}
+	for _, when := range c.Whens {
+		fmt.Fprint(w, ` WHEN `)
+		m.format(w, when.Cond)
+		fmt.Fprint(w, ` THEN `)
+		m.format(w, when.Result)
+	}
+	if c.Else != nil {
+		fmt.Fprint(w, ` ELSE `)
+		m.format(w, c.Else)
+	}
+	fmt.Fprint(w, ` END`)
+}
+
+func (m Mysql) formatWindowFunc(w io.Writer, f *ast.WindowFunc) {
+	m.requireVersion(`OVER`, f, MySQL80, `window functions`)
+
+	m.format(w, f.Func)
+	fmt.Fprint(w, ` OVER `)
+	if f.Window == nil {
+		fmt.Fprint(w, f.WindowName)
+		return
+	}
+	fmt.Fprint(w, `(`)
+	m.format(w, f.Window)
+	fmt.Fprint(w, `)`)
+}
+
+func (m Mysql) formatWindowSpec(w io.Writer, spec *ast.WindowSpec) {
+	sep := ``
+	if len(spec.PartitionBy) > 0 {
+		fmt.Fprint(w, `PARTITION BY `)
+		formatCommaDelimited(w, m, spec.PartitionBy...)
+		sep = ` `
+	}
+	if spec.OrderBy != nil {
+		fmt.Fprint(w, sep)
+		m.format(w, spec.OrderBy)
+		sep = ` `
+	}
+	if spec.Frame != nil {
+		fmt.Fprint(w, sep)
+		formatWindowFrame(w, spec.Frame)
+	}
+}
+
 func (m Mysql) formatIntegerLiteral(w io.Writer, l *ast.IntegerLiteral) {
 	fmt.Fprintf(w, `%d`, l.Value)
 }
 
 func (m Mysql) formatStringLiteral(w io.Writer, l *ast.StringLiteral) {
-	fmt.Fprintf(w, `'%s'`, l.Value)
+	fmt.Fprint(w, m.quoteString(l.Value))
+}
+
+// quoteString quotes s as a MySQL string literal. Backslashes are escape characters in MySQL strings
+// (unless NO_BACKSLASH_ESCAPES is set), so they're escaped along with quotes.
+func (m Mysql) quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `''`)
+	return `'` + s + `'`
 }
 
 func (m Mysql) formatNullLiteral(w io.Writer, _ *ast.NullLiteral) {
 	fmt.Fprint(w, `NULL`)
 }
 
+func (m Mysql) formatBoolLiteral(w io.Writer, l *ast.BoolLiteral) {
+	if l.Value {
+		fmt.Fprint(w, `TRUE`)
+	} else {
+		fmt.Fprint(w, `FALSE`)
+	}
+}
+
+func (m Mysql) formatTimeLiteral(w io.Writer, l *ast.TimeLiteral) {
+	fmt.Fprintf(w, `'%s'`, l.Value.UTC().Format(mysqlTimeFormat))
+}
+
 func (m Mysql) formatTupleLiteral(w io.Writer, t *ast.TupleLiteral) {
 	fmt.Fprint(w, `(`)
 	formatCommaDelimited(w, m, t.Values...)
@@ -296,13 +660,13 @@ func (m Mysql) formatPlaceholderLiteral(w io.Writer, _ *ast.PlaceholderLiteral)
 
 func (m Mysql) formatWhere(w io.Writer, wh *ast.Where) {
 	fmt.Fprint(w, `WHERE `)
-	m.FormatNode(w, wh.Expr)
+	m.format(w, wh.Expr)
 }
 
 func (m Mysql) formatOrderBy(w io.Writer, o *ast.OrderBy) {
 	fmt.Fprint(w, `ORDER BY `)
 	for i, ord := range o.Orders {
-		m.FormatNode(w, ord.Expr)
+		m.format(w, ord.Expr)
 		switch ord.Direction {
 		case ast.OrderAsc:
 			fmt.Fprint(w, ` ASC`)
@@ -332,10 +696,10 @@ func (m Mysql) formatLock(w io.Writer, l *ast.Lock) {
 func (m Mysql) formatLimit(w io.Writer, l *ast.Limit) {
 	fmt.Fprint(w, `LIMIT `)
 	if l.Offset != nil {
-		m.FormatNode(w, l.Offset)
+		m.format(w, l.Offset)
 		fmt.Fprint(w, `, `)
 	}
-	m.FormatNode(w, l.Count)
+	m.format(w, l.Count)
 }
 
 func (m Mysql) formatIdentifier(w io.Writer, c *ast.Identifier) {
@@ -343,23 +707,23 @@ func (m Mysql) formatIdentifier(w io.Writer, c *ast.Identifier) {
 }
 
 func (m Mysql) formatSelector(w io.Writer, s *ast.Selector) {
-	m.FormatNode(w, s.SelectFrom)
+	m.format(w, s.SelectFrom)
 	fmt.Fprint(w, ".")
-	m.FormatNode(w, s.FieldName)
+	m.format(w, s.FieldName)
 }
 
 func (m Mysql) formatValuesLiteral(w io.Writer, vl *ast.ValuesLiteral) {
 	fmt.Fprint(w, `VALUES(`)
-	m.FormatNode(w, vl.Target)
+	m.format(w, vl.Target)
 	fmt.Fprint(w, `)`)
 }
 
 func (m Mysql) formatTableName(w io.Writer, tn *ast.TableName) {
-	m.FormatNode(w, tn.Identifier)
+	m.format(w, tn.Identifier)
 }
 
 func (m Mysql) formatJoin(w io.Writer, j *ast.Join) {
-	m.FormatNode(w, j.Left)
+	m.format(w, j.Left)
 
 	switch j.Kind {
 	case ast.JoinKindInner:
@@ -367,30 +731,30 @@ func (m Mysql) formatJoin(w io.Writer, j *ast.Join) {
 	case ast.JoinKindLeft:
 		fmt.Fprint(w, ` LEFT JOIN `)
 	default:
-		panic(`unexpected join kind`)
+		failf(`JOIN`, j, `unexpected join kind: %v`, j.Kind)
 	}
 
-	m.FormatNode(w, j.Right)
+	m.format(w, j.Right)
 	fmt.Fprint(w, ` ON `)
-	m.FormatNode(w, j.On)
+	m.format(w, j.On)
 }
 
 func (m Mysql) formatAlias(w io.Writer, a *ast.Alias) {
-	m.FormatNode(w, a.ForExpr)
+	m.format(w, a.ForExpr)
 	fmt.Fprint(w, ` AS `)
-	m.FormatNode(w, a.As)
+	m.format(w, a.As)
 }
 
 func (m Mysql) formatTableAlias(w io.Writer, a *ast.TableAlias) {
-	m.FormatNode(w, a.ForExpr)
+	m.format(w, a.ForExpr)
 	fmt.Fprint(w, ` AS `)
-	m.FormatNode(w, a.As)
+	m.format(w, a.As)
 }
 
 func (m Mysql) formatUnaryExpr(w io.Writer, un *ast.UnaryExpr) {
 	// For postfix operators, we format the operand before the operator.
 	if un.Op.IsPost() {
-		m.FormatNode(w, un.Operand)
+		m.formatOperand(w, un, un.Operand, false)
 	}
 
 	switch un.Op {
@@ -398,18 +762,26 @@ func (m Mysql) formatUnaryExpr(w io.Writer, un *ast.UnaryExpr) {
 		fmt.Fprint(w, " IS NOT NULL")
 	case ast.UnaryIsNull:
 		fmt.Fprint(w, " IS NULL")
+	case ast.UnaryNot:
+		// NOT binds more loosely than comparisons but more tightly than AND and OR, so its operand
+		// is always parenthesized.
+		fmt.Fprint(w, "NOT (")
 	default:
-		panic(fmt.Sprintf("unexpected ast.UnaryExprOperator: %#v", un.Op))
+		failf(``, un, `unsupported unary operation: %v`, un.Op)
 	}
 
 	// For prefix operators, we format the operand after the operator.
 	if !un.Op.IsPost() {
-		m.FormatNode(w, un.Operand)
+		m.format(w, un.Operand)
+	}
+
+	if un.Op == ast.UnaryNot {
+		fmt.Fprint(w, ")")
 	}
 }
 
 func (m Mysql) formatBinaryExpr(w io.Writer, bin *ast.BinaryExpr) {
-	m.FormatNode(w, bin.Left)
+	m.formatOperand(w, bin, bin.Left, false)
 
 	switch bin.Op {
 	case ast.BinaryEquals:
@@ -430,11 +802,56 @@ func (m Mysql) formatBinaryExpr(w io.Writer, bin *ast.BinaryExpr) {
 		fmt.Fprint(w, ` AND `)
 	case ast.BinaryOr:
 		fmt.Fprint(w, ` OR `)
+	case ast.BinaryNotIn:
+		fmt.Fprint(w, ` NOT IN `)
+	case ast.BinaryLike:
+		fmt.Fprint(w, ` LIKE `)
+	case ast.BinaryNotLike:
+		fmt.Fprint(w, ` NOT LIKE `)
+	default:
+		failf(``, bin, `unsupported binary operation: %v`, bin.Op)
+	}
+
+	m.formatOperand(w, bin, bin.Right, true)
+}
+
+// formatOperand formats an operand of parent, parenthesizing it if it binds more loosely than parent.
+func (m Mysql) formatOperand(w io.Writer, parent, operand ast.Expr, right bool) {
+	if !ast.NeedsParens(parent, operand, right) {
+		m.format(w, operand)
+		return
+	}
+
+	fmt.Fprint(w, `(`)
+	m.format(w, operand)
+	fmt.Fprint(w, `)`)
+}
+
+func (m Mysql) formatTernaryExpr(w io.Writer, t *ast.TernaryExpr) {
+	m.formatOperand(w, t, t.First, false)
+
+	switch t.Op {
+	case ast.TernaryBetween:
+		fmt.Fprint(w, ` BETWEEN `)
+		m.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` AND `)
+	case ast.TernaryNotBetween:
+		fmt.Fprint(w, ` NOT BETWEEN `)
+		m.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` AND `)
+	case ast.TernaryLikeEscape:
+		fmt.Fprint(w, ` LIKE `)
+		m.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` ESCAPE `)
+	case ast.TernaryNotLikeEscape:
+		fmt.Fprint(w, ` NOT LIKE `)
+		m.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` ESCAPE `)
 	default:
-		panic(fmt.Sprintf(`unsupported binary operation: %v`, bin.Op))
+		failf(``, t, `unsupported ternary operation: %v`, t.Op)
 	}
 
-	m.FormatNode(w, bin.Right)
+	m.formatOperand(w, t, t.Third, true)
 }
 
 func (m Mysql) formatDistinct(w io.Writer, d *ast.Distinct) {
@@ -0,0 +1,440 @@
+package formatter
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/scan"
+)
+
+const defaultPrettyWidth = 80
+
+// Pretty wraps another formatter (Mysql or Sqlite) and spreads its output over multiple lines. Each
+// clause starts a new line, joins are indented under FROM, conditions break before AND/OR with nested
+// groups and subqueries indented, and lists that don't fit in Width are put one item per line.
+//
+// Only whitespace outside of quotes and comments is changed, so the statement and the order of its
+// arguments are the same as the wrapped formatter's. A line comment (-- or #), e.g. from raw SQL, is
+// always followed by a line break, so it can't swallow what comes after it.
+type Pretty struct {
+	Formatter interface {
+		FormatNode(w io.Writer, n ast.Node) error
+	}
+	// Width is the line length past which lists are wrapped. It defaults to 80.
+	Width int
+}
+
+func (p Pretty) FormatNode(w io.Writer, n ast.Node) error {
+	sb := &strings.Builder{}
+	if err := p.Formatter.FormatNode(sb, n); err != nil {
+		return err
+	}
+
+	width := p.Width
+	if width <= 0 {
+		width = defaultPrettyWidth
+	}
+
+	e := &prettyEmitter{width: width}
+	e.statement(parsePretty(tokenizePretty(sb.String(), scan.Of(p.Formatter))), 0)
+	_, err := io.WriteString(w, e.sb.String())
+	return err
+}
+
+// EmulatesCreateLike reports whether the wrapped formatter emulates CREATE TABLE ... LIKE.
+func (p Pretty) EmulatesCreateLike() bool {
+	e, ok := p.Formatter.(interface{ EmulatesCreateLike() bool })
+	return ok && e.EmulatesCreateLike()
+}
+
+// ConvertArg converts v like the wrapped formatter does, if it converts arguments.
+func (p Pretty) ConvertArg(v any) any {
+	if c, ok := p.Formatter.(ast.ArgConverter); ok {
+		return c.ConvertArg(v)
+	}
+	return v
+}
+
+// ScanDialect describes the wrapped formatter's dialect.
+func (p Pretty) ScanDialect() scan.Dialect {
+	return scan.Of(p.Formatter)
+}
+
+// Literal renders v using the wrapped formatter, so that Pretty can be used with
+// statement.Statement.Interpolate.
+func (p Pretty) Literal(v driver.Value) (string, error) {
+	d, ok := p.Formatter.(interface {
+		Literal(v driver.Value) (string, error)
+	})
+	if !ok {
+		return ``, fmt.Errorf(`%T cannot render literals`, p.Formatter)
+	}
+	return d.Literal(v)
+}
+
+type prettyToken struct {
+	text string
+	// space is whether the token was preceded by whitespace.
+	space bool
+	// lineComment is whether the token is a comment which runs to the end of the line.
+	lineComment bool
+}
+
+// tokenizePretty splits formatted SQL into words, quoted strings and identifiers, comments, parentheses
+// and commas. Quoted tokens and comments are kept intact.
+func tokenizePretty(sql string, d scan.Dialect) []prettyToken {
+	var (
+		toks  []prettyToken
+		space bool
+		// word is whether the last token can be extended, e.g. a = b is three tokens, but a=b is one.
+		word bool
+	)
+	for _, t := range scan.Tokens(sql, d) {
+		switch {
+		case t.Kind == scan.Space:
+			space = true
+			word = false
+			continue
+		case t.Kind == scan.Word || t.Kind == scan.Number || t.Kind == scan.Placeholder ||
+			t.Kind == scan.Punct && t.Text != `(` && t.Text != `)` && t.Text != `,`:
+			if word && !space {
+				toks[len(toks)-1].text += t.Text
+				continue
+			}
+			word = true
+		default:
+			word = false
+		}
+		toks = append(toks, prettyToken{text: t.Text, space: space, lineComment: t.Kind == scan.LineComment})
+		space = false
+	}
+	return toks
+}
+
+// prettyNode is either a single token or a parenthesized group.
+type prettyNode struct {
+	tok prettyToken
+
+	isGroup bool
+	open    prettyToken
+	nodes   []*prettyNode
+	close   prettyToken
+}
+
+func (n *prettyNode) space() bool {
+	if n.isGroup {
+		return n.open.space
+	}
+	return n.tok.space
+}
+
+func (n *prettyNode) is(text string) bool {
+	return !n.isGroup && n.tok.text == text
+}
+
+func parsePretty(toks []prettyToken) []*prettyNode {
+	nodes, _ := parsePrettyGroup(toks)
+	return nodes
+}
+
+func parsePrettyGroup(toks []prettyToken) ([]*prettyNode, []prettyToken) {
+	var nodes []*prettyNode
+	for len(toks) > 0 {
+		tok := toks[0]
+		toks = toks[1:]
+		switch tok.text {
+		case `(`:
+			g := &prettyNode{isGroup: true, open: tok}
+			g.nodes, toks = parsePrettyGroup(toks)
+			if len(toks) > 0 {
+				g.close, toks = toks[0], toks[1:]
+			}
+			nodes = append(nodes, g)
+		case `)`:
+			return nodes, append([]prettyToken{tok}, toks...)
+		default:
+			nodes = append(nodes, &prettyNode{tok: tok})
+		}
+	}
+	return nodes, nil
+}
+
+type prettyClauseKind int
+
+const (
+	prettyClauseInline prettyClauseKind = iota
+	prettyClauseList
+	prettyClauseCondition
+	prettyClauseJoin
+)
+
+type prettyClause struct {
+	keyword []string
+	kind    prettyClauseKind
+}
+
+// prettyClauses are the keywords which start a new line. Longer keywords come first so that e.g.
+// DELETE FROM isn't mistaken for FROM.
+var prettyClauses = []prettyClause{
+	{[]string{`ON`, `DUPLICATE`, `KEY`, `UPDATE`}, prettyClauseList},
+	{[]string{`DO`, `UPDATE`, `SET`}, prettyClauseList},
+	{[]string{`ON`, `CONFLICT`}, prettyClauseInline},
+	{[]string{`DELETE`, `FROM`}, prettyClauseInline},
+	{[]string{`INSERT`, `INTO`}, prettyClauseInline},
+	{[]string{`INNER`, `JOIN`}, prettyClauseJoin},
+	{[]string{`LEFT`, `JOIN`}, prettyClauseJoin},
+	{[]string{`GROUP`, `BY`}, prettyClauseList},
+	{[]string{`ORDER`, `BY`}, prettyClauseList},
+	{[]string{`FOR`, `UPDATE`}, prettyClauseInline},
+	{[]string{`FOR`, `SHARE`}, prettyClauseInline},
+	{[]string{`SELECT`}, prettyClauseList},
+	{[]string{`FROM`}, prettyClauseInline},
+	{[]string{`WHERE`}, prettyClauseCondition},
+	{[]string{`HAVING`}, prettyClauseCondition},
+	{[]string{`WINDOW`}, prettyClauseList},
+	{[]string{`LIMIT`}, prettyClauseInline},
+	{[]string{`UPDATE`}, prettyClauseInline},
+	{[]string{`SET`}, prettyClauseList},
+	{[]string{`VALUES`}, prettyClauseList},
+}
+
+// matchClause returns the clause starting at nodes[i], if any. A keyword must be followed by whitespace
+// (or end the statement), which keeps function calls like MySQL's VALUES(x) from matching.
+func matchClause(nodes []*prettyNode, i int) (prettyClause, bool) {
+outer:
+	for _, c := range prettyClauses {
+		if i+len(c.keyword) > len(nodes) {
+			continue
+		}
+		for j, kw := range c.keyword {
+			n := nodes[i+j]
+			if !n.is(kw) || (j > 0 && !n.space()) {
+				continue outer
+			}
+		}
+		if next := i + len(c.keyword); next < len(nodes) && !nodes[next].space() {
+			continue
+		}
+		return c, true
+	}
+	return prettyClause{}, false
+}
+
+type prettyEmitter struct {
+	sb     strings.Builder
+	width  int
+	indent int
+	// lineStart is whether nothing has been written on the current line yet.
+	lineStart bool
+	// inComment is whether the current line ends in a line comment, so nothing else can go on it.
+	inComment bool
+}
+
+func (e *prettyEmitter) newline(indent int) {
+	e.sb.WriteString("\n")
+	e.sb.WriteString(strings.Repeat(`  `, indent))
+	e.indent = indent
+	e.lineStart = true
+	e.inComment = false
+}
+
+func (e *prettyEmitter) write(s string, space bool) {
+	if e.inComment {
+		e.newline(e.indent)
+	}
+	if space && !e.lineStart && e.sb.Len() > 0 {
+		e.sb.WriteString(` `)
+	}
+	e.sb.WriteString(s)
+	e.lineStart = false
+	e.inComment = false
+}
+
+// statement lays out a statement with each clause on its own line.
+func (e *prettyEmitter) statement(nodes []*prettyNode, indent int) {
+	type span struct {
+		clause prettyClause
+		nodes  []*prettyNode
+	}
+
+	var spans []span
+	cur := span{}
+	for i := 0; i < len(nodes); {
+		if c, ok := matchClause(nodes, i); ok {
+			if len(cur.nodes) > 0 {
+				spans = append(spans, cur)
+			}
+			cur = span{clause: c, nodes: nodes[i : i+len(c.keyword) : i+len(c.keyword)]}
+			i += len(c.keyword)
+			continue
+		}
+		cur.nodes = append(cur.nodes, nodes[i])
+		i++
+	}
+	if len(cur.nodes) > 0 {
+		spans = append(spans, cur)
+	}
+
+	for i, s := range spans {
+		clauseIndent := indent
+		if s.clause.kind == prettyClauseJoin {
+			clauseIndent++
+		}
+		if i > 0 {
+			e.newline(clauseIndent)
+		}
+
+		kw, body := s.nodes[:len(s.clause.keyword)], s.nodes[len(s.clause.keyword):]
+		switch s.clause.kind {
+		case prettyClauseList:
+			e.list(kw, body, clauseIndent)
+		case prettyClauseCondition:
+			e.inline(kw)
+			e.condition(body, clauseIndent+1)
+		case prettyClauseJoin:
+			on := len(body)
+			for j, n := range body {
+				if n.is(`ON`) {
+					on = j
+					break
+				}
+			}
+			e.inline(kw)
+			e.inline(body[:on])
+			if on < len(body) {
+				e.inline(body[on : on+1])
+				e.condition(body[on+1:], clauseIndent+1)
+			}
+		default:
+			e.inline(s.nodes)
+		}
+	}
+}
+
+// list lays out a comma separated list, one item per line if it doesn't fit on the current line.
+func (e *prettyEmitter) list(kw, body []*prettyNode, indent int) {
+	trial := &prettyEmitter{width: e.width, indent: indent, lineStart: true}
+	trial.inline(kw)
+	trial.inline(body)
+	if s := trial.sb.String(); !strings.Contains(s, "\n") && 2*indent+len(s) <= e.width {
+		e.inline(kw)
+		e.inline(body)
+		return
+	}
+
+	// Modifiers like DISTINCT stay on the keyword's line.
+	e.inline(kw)
+	for len(body) > 0 && body[0].is(`DISTINCT`) {
+		e.inline(body[:1])
+		body = body[1:]
+	}
+
+	for _, item := range splitPretty(body, func(n *prettyNode) bool { return n.is(`,`) }) {
+		if item.sep != nil {
+			e.write(item.sep.tok.text, false)
+		}
+		e.newline(indent + 1)
+		e.inline(item.nodes)
+	}
+}
+
+// condition lays out a boolean expression, breaking before each top level OR (or AND, if there are no
+// ORs). Continuation lines are indented to the given level.
+func (e *prettyEmitter) condition(nodes []*prettyNode, indent int) {
+	op := `AND`
+	if len(splitBoolean(nodes, `OR`)) > 1 {
+		op = `OR`
+	}
+
+	for _, part := range splitBoolean(nodes, op) {
+		if part.sep != nil {
+			e.newline(indent)
+			e.write(part.sep.tok.text, false)
+		}
+		e.inline(part.nodes)
+	}
+}
+
+// inline writes nodes on the current line, preserving their spacing. Boolean groups and subqueries
+// are still broken over multiple lines.
+func (e *prettyEmitter) inline(nodes []*prettyNode) {
+	for _, n := range nodes {
+		if !n.isGroup {
+			e.write(n.tok.text, n.tok.space)
+			e.inComment = n.tok.lineComment
+			continue
+		}
+
+		indent := e.indent
+		e.write(n.open.text, n.open.space)
+		switch {
+		case len(n.nodes) > 0 && n.nodes[0].is(`SELECT`):
+			e.newline(indent + 1)
+			e.statement(n.nodes, indent+1)
+			e.newline(indent)
+		case isBooleanGroup(n.nodes):
+			e.newline(indent + 1)
+			e.condition(n.nodes, indent+1)
+			e.newline(indent)
+		default:
+			e.inline(n.nodes)
+		}
+		if n.close.text != `` {
+			e.write(n.close.text, n.close.space)
+		}
+	}
+}
+
+type prettyPart struct {
+	// sep is the separator before this part, nil for the first part.
+	sep   *prettyNode
+	nodes []*prettyNode
+}
+
+func splitPretty(nodes []*prettyNode, isSep func(n *prettyNode) bool) []prettyPart {
+	parts := []prettyPart{{}}
+	for _, n := range nodes {
+		if isSep(n) {
+			parts = append(parts, prettyPart{sep: n})
+			continue
+		}
+		last := &parts[len(parts)-1]
+		last.nodes = append(last.nodes, n)
+	}
+	return parts
+}
+
+// splitBoolean splits nodes at each top level op. Operators inside CASE expressions and the AND of
+// BETWEEN aren't split on.
+func splitBoolean(nodes []*prettyNode, op string) []prettyPart {
+	caseDepth := 0
+	inBetween := false
+	return splitPretty(nodes, func(n *prettyNode) bool {
+		switch {
+		case n.is(`CASE`):
+			caseDepth++
+		case n.is(`END`):
+			caseDepth--
+		case n.is(`BETWEEN`):
+			inBetween = true
+		case n.is(`AND`) && inBetween:
+			inBetween = false
+			return false
+		}
+		return caseDepth == 0 && n.is(op) && n.space()
+	})
+}
+
+// isBooleanGroup reports whether the contents of a parenthesized group are an AND or OR expression, as
+// opposed to e.g. function arguments or a tuple.
+func isBooleanGroup(nodes []*prettyNode) bool {
+	for _, n := range nodes {
+		if n.is(`,`) {
+			return false
+		}
+	}
+	return len(splitBoolean(nodes, `AND`)) > 1 || len(splitBoolean(nodes, `OR`)) > 1
+}
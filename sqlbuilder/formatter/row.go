@@ -0,0 +1,47 @@
+package formatter
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+
+// expandRowIn rewrites (a, b) IN ((?, ?), (?, ?)) as a = ? AND b = ? OR a = ? AND b = ?, for dialects
+// which can't compare a row value against a list of rows. The placeholders keep their order, so the
+// statement's args don't change. ok is false if bin isn't a row value IN or NOT IN.
+func expandRowIn(bin *ast.BinaryExpr) (ast.Expr, bool) {
+	if bin.Op != ast.BinaryIn && bin.Op != ast.BinaryNotIn {
+		return nil, false
+	}
+	left, ok := bin.Left.(*ast.TupleLiteral)
+	if !ok {
+		return nil, false
+	}
+	right, ok := bin.Right.(*ast.TupleLiteral)
+	if !ok {
+		return nil, false
+	}
+
+	var res ast.Expr
+	for _, r := range right.Values {
+		row, ok := r.(*ast.TupleLiteral)
+		if !ok {
+			return nil, false
+		}
+
+		var match ast.Expr
+		for i, col := range left.Values {
+			eq := &ast.BinaryExpr{Left: col, Op: ast.BinaryEquals, Right: row.Values[i]}
+			match = chainExprs(match, ast.BinaryAnd, eq)
+		}
+		res = chainExprs(res, ast.BinaryOr, match)
+	}
+
+	if bin.Op == ast.BinaryNotIn {
+		return &ast.UnaryExpr{Operand: res, Op: ast.UnaryNot}, true
+	}
+	return res, true
+}
+
+func chainExprs(left ast.Expr, op ast.BinaryExprOperator, right ast.Expr) ast.Expr {
+	if left == nil {
+		return right
+	}
+	return &ast.BinaryExpr{Left: left, Op: op, Right: right}
+}
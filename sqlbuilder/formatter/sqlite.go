@@ -1,15 +1,77 @@
 package formatter
 
 import (
+	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/scan"
 )
 
 type Sqlite struct{}
 
-func (s Sqlite) FormatNode(w io.Writer, n ast.Node) {
+func (s Sqlite) FormatNode(w io.Writer, n ast.Node) (err error) {
+	if err := ast.Validate(n); err != nil {
+		return err
+	}
+
+	defer recoverFormatError(&err)
+	s.format(w, n)
+	return nil
+}
+
+// EmulatesCreateLike reports that CREATE TABLE ... LIKE needs the other table's definition (see
+// ast.CreateTable.LikeDefinition), which table.CreateBuilder reads from sqlite_master.
+func (s Sqlite) EmulatesCreateLike() bool {
+	return true
+}
+
+// ConvertArg formats a time.Time argument with SqliteTimeLayout, rather than leaving it to the driver,
+// which keeps its zone and drops trailing zeros, so that it compares correctly with the times the
+// formatter writes.
+func (s Sqlite) ConvertArg(v any) any {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(SqliteTimeLayout)
+	}
+	return v
+}
+
+// ScanDialect describes how SQLite quotes strings and identifiers and writes comments, so that
+// Pretty and statement.Interpolate can find their way around its SQL.
+func (s Sqlite) ScanDialect() scan.Dialect {
+	return scan.Sqlite
+}
+
+// Literal renders an argument as a Sqlite literal, for statement.Statement.Interpolate.
+func (s Sqlite) Literal(v driver.Value) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return `NULL`, nil
+	case bool:
+		if v {
+			return `1`, nil
+		}
+		return `0`, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case string:
+		return s.quoteString(v), nil
+	case []byte:
+		return `X'` + hex.EncodeToString(v) + `'`, nil
+	case time.Time:
+		return s.quoteString(v.UTC().Format(SqliteTimeLayout)), nil
+	}
+	return ``, fmt.Errorf(`cannot render %T as a literal`, v)
+}
+
+func (s Sqlite) format(w io.Writer, n ast.Node) {
 	switch tn := n.(type) {
 	case *ast.Select:
 		s.formatSelect(w, tn)
@@ -21,17 +83,25 @@ func (s Sqlite) FormatNode(w io.Writer, n ast.Node) {
 		s.formatUpdate(w, tn)
 	case *ast.CreateTable:
 		s.formatCreateTable(w, tn)
+	case *ast.CreateView:
+		s.formatCreateView(w, tn)
+	case *ast.DropView:
+		s.formatDropView(w, tn)
 	case *ast.ColumnSpec:
 		s.formatColumnSpec(w, tn)
 	case ast.ColumnType:
 		s.formatColumnType(w, tn)
 	case *ast.ColumnDefault:
 		s.formatColumnDefault(w, tn)
+	case *ast.GeneratedColumn:
+		s.formatGeneratedColumn(w, tn)
 	case ast.Nullability:
 		s.formatNullability(w, tn)
 	case *ast.PrimaryKey:
 		// Primary keys are added to columns in the column definition during a CREATE TABLE
 		return
+	case *ast.TableOption:
+		s.formatTableOption(w, tn)
 	case *ast.TableName:
 		s.formatTableName(w, tn)
 	case *ast.Join:
@@ -56,6 +126,8 @@ func (s Sqlite) FormatNode(w io.Writer, n ast.Node) {
 		s.formatUnaryExpr(w, tn)
 	case *ast.BinaryExpr:
 		s.formatBinaryExpr(w, tn)
+	case *ast.TernaryExpr:
+		s.formatTernaryExpr(w, tn)
 	case *ast.PlaceholderLiteral:
 		s.formatPlaceholderLiteral(w, tn)
 	case *ast.TupleLiteral:
@@ -66,10 +138,33 @@ func (s Sqlite) FormatNode(w io.Writer, n ast.Node) {
 		s.formatStringLiteral(w, tn)
 	case *ast.NullLiteral:
 		s.formatNullLiteral(w, tn)
+	case *ast.BoolLiteral:
+		s.formatBoolLiteral(w, tn)
+	case *ast.TimeLiteral:
+		s.formatTimeLiteral(w, tn)
+	case *ast.CurrentTimestampLiteral:
+		// SQLite's CURRENT_TIMESTAMP is YYYY-MM-DD HH:MM:SS, which doesn't compare with other times.
+		s.formatBuiltin(w, ast.NewBuiltin(ast.BuiltinNow))
 	case *ast.OrderBy:
 		s.formatOrderBy(w, tn)
 	case *ast.Function:
 		s.formatFunction(w, tn)
+	case *ast.Case:
+		s.formatCase(w, tn)
+	case *ast.Raw:
+		s.formatRaw(w, tn)
+	case *ast.WindowFunc:
+		s.formatWindowFunc(w, tn)
+	case *ast.WindowSpec:
+		s.formatWindowSpec(w, tn)
+	case *ast.Builtin:
+		s.formatBuiltin(w, tn)
+	case *ast.GroupConcat:
+		s.formatGroupConcat(w, tn)
+	case *ast.Subquery:
+		fmt.Fprint(w, `(`)
+		s.formatSelect(w, tn.Select)
+		fmt.Fprint(w, `)`)
 	case *ast.StarLiteral:
 		fmt.Fprint(w, "*")
 	case *ast.Distinct:
@@ -77,90 +172,104 @@ func (s Sqlite) FormatNode(w io.Writer, n ast.Node) {
 	case *ast.OnDuplicateKey:
 		s.formatOnDuplicateKey(w, tn)
 	default:
-		panic(fmt.Sprintf(`unexpected node: %T`, n))
+		failf(``, n, `unexpected node: %T`, n)
 	}
 }
 
 func (s Sqlite) formatSelect(w io.Writer, sl *ast.Select) {
 	fmt.Fprint(w, `SELECT `)
 	for i, expr := range sl.Exprs {
-		s.FormatNode(w, expr)
+		s.format(w, expr)
 		if i < len(sl.Exprs)-1 {
 			fmt.Fprint(w, `,`)
 		}
 	}
 
 	fmt.Fprint(w, ` FROM `)
-	s.FormatNode(w, sl.From)
+	s.format(w, sl.From)
 
 	if sl.Where != nil {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, sl.Where)
+		s.format(w, sl.Where)
+	}
+	if sl.GroupBy != nil {
+		fmt.Fprint(w, ` GROUP BY `)
+		formatCommaDelimited(w, s, sl.GroupBy.Exprs...)
+	}
+	if sl.Having != nil {
+		fmt.Fprint(w, ` HAVING `)
+		s.format(w, sl.Having.Expr)
+	}
+	if len(sl.Windows) > 0 {
+		fmt.Fprint(w, ` WINDOW `)
+		for i, win := range sl.Windows {
+			if i > 0 {
+				fmt.Fprint(w, `,`)
+			}
+			fmt.Fprintf(w, `%s AS (`, win.Name)
+			s.format(w, win.Spec)
+			fmt.Fprint(w, `)`)
+		}
 	}
 	if sl.OrderBy != nil {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, sl.OrderBy)
+		s.format(w, sl.OrderBy)
 	}
 	if sl.Limit != nil {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, sl.Limit)
-	}
-	if sl.Lock != nil {
-		fmt.Fprint(w, ` `)
-		s.FormatNode(w, sl.Lock)
+		s.format(w, sl.Limit)
 	}
+	// Locks are dropped; see formatLock.
 }
 
 func (s Sqlite) formatDelete(w io.Writer, d *ast.Delete) {
 	fmt.Fprint(w, `DELETE FROM `)
-	s.FormatNode(w, d.From)
+	s.format(w, d.From)
 
 	if d.Where != nil {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, d.Where)
+		s.format(w, d.Where)
 	}
 	if d.OrderBy != nil {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, d.OrderBy)
+		s.format(w, d.OrderBy)
 	}
 	if d.Limit != nil {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, d.Limit)
+		s.format(w, d.Limit)
 	}
 }
 
 func (s Sqlite) formatInsert(w io.Writer, i *ast.Insert) {
 	fmt.Fprint(w, `INSERT INTO `)
-	s.FormatNode(w, i.Into)
+	s.format(w, i.Into)
 	fmt.Fprint(w, ` (`)
 	formatCommaDelimited(w, s, i.Columns...)
 	fmt.Fprint(w, `) VALUES `)
 	formatCommaDelimited(w, s, i.Values...)
 	if i.OnDuplicateKey != nil {
-		s.FormatNode(w, i.OnDuplicateKey)
+		s.format(w, i.OnDuplicateKey)
 	}
 }
 
 func (s Sqlite) formatUpdate(w io.Writer, u *ast.Update) {
 	fmt.Fprint(w, `UPDATE `)
-	s.FormatNode(w, u.Table)
+	s.format(w, u.Table)
 	fmt.Fprint(w, ` SET `)
 	formatCommaDelimited(w, s, u.AssignmentList...)
 
 	if u.Where != nil {
 		fmt.Fprintf(w, ` `)
-		s.FormatNode(w, u.Where)
+		s.format(w, u.Where)
 	}
 
-	// TODO we "support" these in the formatter, but we don't expose them to the public via the builders.
-	// Add tests for these once we support them publicly.
 	if u.OrderBy != nil {
-		fmt.Fprint(w, ` ORDER BY `)
-		s.FormatNode(w, u.OrderBy)
+		fmt.Fprint(w, ` `)
+		s.format(w, u.OrderBy)
 	}
 	if u.Limit != nil {
-		fmt.Fprint(w, ` LIMIT `)
-		s.FormatNode(w, u.Limit)
+		fmt.Fprint(w, ` `)
+		s.format(w, u.Limit)
 	}
 }
 
@@ -170,31 +279,150 @@ func (s Sqlite) formatCreateTable(w io.Writer, ct *ast.CreateTable) {
 		fmt.Fprint(w, `IF NOT EXISTS `)
 	}
 
-	s.FormatNode(w, ct.Name)
+	s.format(w, ct.Name)
+
+	if ct.Like != nil {
+		s.formatCreateTableLike(w, ct)
+		return
+	}
+
+	if ct.AsSelect != nil {
+		if len(ct.Columns) > 0 || len(ct.Options) > 0 {
+			failf(`CREATE TABLE`, ct, `SQLite does not support columns or table options in CREATE TABLE ... AS SELECT`)
+		}
+		fmt.Fprint(w, ` AS `)
+		s.format(w, ct.AsSelect)
+		return
+	}
 
 	fmt.Fprint(w, `(`)
 	formatCommaDelimited(w, s, ct.Columns...)
 
 	fmt.Fprint(w, `)`)
+
+	if ct.HasOption(ast.TableOptionStrict) {
+		for _, col := range ct.Columns {
+			switch col.Type.(type) {
+			case ast.DateTimeColumn, ast.TimestampColumn:
+				failf(`CREATE TABLE`, col, `SQLite STRICT tables do not allow NUMERIC columns, which %s would be`, col.Name.Name)
+			}
+		}
+	}
+
+	// SQLite's table options are comma-delimited, e.g. WITHOUT ROWID,STRICT
+	if len(ct.Options) > 0 {
+		fmt.Fprint(w, ` `)
+		formatCommaDelimited(w, s, ct.Options...)
+	}
+}
+
+// formatCreateTableLike emulates CREATE TABLE ... LIKE, which SQLite lacks, by copying everything
+// after the table name from the other table's definition (as stored in sqlite_master). Unlike MySQL's
+// LIKE, this doesn't copy the other table's indexes, which SQLite stores separately.
+func (s Sqlite) formatCreateTableLike(w io.Writer, ct *ast.CreateTable) {
+	if ct.LikeDefinition == `` {
+		failf(
+			`CREATE TABLE`,
+			ct,
+			`SQLite does not support CREATE TABLE ... LIKE; it is emulated using the definition of %s from sqlite_master, which requires executing the statement against the database`,
+			ct.Like.Name,
+		)
+	}
+
+	rest, ok := strings.CutPrefix(ct.LikeDefinition, `CREATE TABLE `)
+	if !ok || rest == `` {
+		failf(`CREATE TABLE`, ct, `unexpected definition for table %s: %s`, ct.Like.Name, ct.LikeDefinition)
+	}
+
+	// Skip over the (possibly quoted) table name.
+	end := -1
+	switch rest[0] {
+	case '"', '`', '\'':
+		if i := strings.IndexByte(rest[1:], rest[0]); i >= 0 {
+			end = i + 2
+		}
+	case '[':
+		if i := strings.IndexByte(rest, ']'); i >= 0 {
+			end = i + 1
+		}
+	default:
+		end = strings.IndexAny(rest, " \t\n(")
+	}
+	if end <= 0 {
+		failf(`CREATE TABLE`, ct, `unexpected definition for table %s: %s`, ct.Like.Name, ct.LikeDefinition)
+	}
+
+	fmt.Fprint(w, rest[end:])
+}
+
+func (s Sqlite) formatTableOption(w io.Writer, opt *ast.TableOption) {
+	switch opt.Kind {
+	case ast.TableOptionWithoutRowID:
+		fmt.Fprint(w, `WITHOUT ROWID`)
+	case ast.TableOptionStrict:
+		fmt.Fprint(w, `STRICT`)
+	default:
+		failf(`CREATE TABLE`, opt, `SQLite does not support table option %s`, opt.Kind)
+	}
+}
+
+func (s Sqlite) formatCreateView(w io.Writer, cv *ast.CreateView) {
+	if cv.OrReplace {
+		failf(`CREATE VIEW`, cv, `SQLite does not support CREATE OR REPLACE VIEW`)
+	}
+	fmt.Fprint(w, `CREATE VIEW `)
+	if cv.IfNotExists {
+		fmt.Fprint(w, `IF NOT EXISTS `)
+	}
+
+	s.format(w, cv.Name)
+
+	if len(cv.Columns) > 0 {
+		fmt.Fprint(w, `(`)
+		formatCommaDelimited(w, s, cv.Columns...)
+		fmt.Fprint(w, `)`)
+	}
+
+	fmt.Fprint(w, ` AS `)
+	s.format(w, cv.As)
+}
+
+func (s Sqlite) formatDropView(w io.Writer, dv *ast.DropView) {
+	fmt.Fprint(w, `DROP VIEW `)
+	if dv.IfExists {
+		fmt.Fprint(w, `IF EXISTS `)
+	}
+	s.format(w, dv.Name)
 }
 
 func (s Sqlite) formatColumnSpec(w io.Writer, cs *ast.ColumnSpec) {
-	s.FormatNode(w, cs.Name)
+	s.format(w, cs.Name)
 	fmt.Fprint(w, ` `)
-	s.FormatNode(w, cs.Type)
+	s.format(w, cs.Type)
+	if cs.Collation != `` {
+		fmt.Fprintf(w, ` COLLATE %s`, cs.Collation)
+	}
+	if cs.Generated != nil {
+		fmt.Fprint(w, ` `)
+		s.format(w, cs.Generated)
+	}
 	if cs.Nullability != ast.NoNullability {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, cs.Nullability)
+		s.format(w, cs.Nullability)
 	}
 	if cs.Default != nil {
 		fmt.Fprint(w, ` `)
-		s.FormatNode(w, cs.Default)
+		s.format(w, cs.Default)
 	}
 	if cs.ComprisesPrimaryKey {
 		fmt.Fprint(w, ` PRIMARY KEY`)
 	}
 
-	// SQLite has no concept of auto_increment
+	if cs.Comment != `` {
+		failf(`CREATE TABLE`, cs, `SQLite does not support column comments (on %s)`, cs.Name.Name)
+	}
+
+	// SQLite has no concept of auto_increment or ON UPDATE
 }
 
 func (s Sqlite) formatColumnType(w io.Writer, ct ast.ColumnType) {
@@ -205,14 +433,32 @@ func (s Sqlite) formatColumnType(w io.Writer, ct ast.ColumnType) {
 		fmt.Fprint(w, `TEXT`)
 	case ast.TinyBlobColumn, ast.BlobColumn, ast.MediumBlobColumn, ast.LongBlobColumn:
 		fmt.Fprint(w, `BLOB`)
-	case ast.DateTimeColumn:
+	case ast.DateTimeColumn, ast.TimestampColumn:
 		fmt.Fprint(w, `NUMERIC`)
 	}
 }
 
 func (s Sqlite) formatColumnDefault(w io.Writer, cd *ast.ColumnDefault) {
 	fmt.Fprint(w, `DEFAULT `)
-	s.FormatNode(w, cd.Value)
+	if _, ok := cd.Value.(*ast.CurrentTimestampLiteral); !ok && cd.IsLiteral() {
+		s.format(w, cd.Value)
+		return
+	}
+
+	fmt.Fprint(w, `(`)
+	s.format(w, cd.Value)
+	fmt.Fprint(w, `)`)
+}
+
+func (s Sqlite) formatGeneratedColumn(w io.Writer, g *ast.GeneratedColumn) {
+	fmt.Fprint(w, `GENERATED ALWAYS AS (`)
+	s.format(w, g.Expr)
+	fmt.Fprint(w, `)`)
+	if g.Stored {
+		fmt.Fprint(w, ` STORED`)
+	} else {
+		fmt.Fprint(w, ` VIRTUAL`)
+	}
 }
 
 func (s Sqlite) formatNullability(w io.Writer, n ast.Nullability) {
@@ -227,28 +473,200 @@ func (s Sqlite) formatNullability(w io.Writer, n ast.Nullability) {
 func (s Sqlite) formatFunction(w io.Writer, f *ast.Function) {
 	fmt.Fprint(w, f.Name)
 	fmt.Fprint(w, `(`)
-	for _, arg := range f.Args {
-		s.FormatNode(w, arg)
+	formatCommaDelimited(w, s, f.Args...)
+	fmt.Fprint(w, `)`)
+}
+
+// SqliteTimeLayout is the layout of every time the Sqlite formatter writes: time literals, Now,
+// TruncTime and CURRENT_TIMESTAMP, as well as time.Time arguments (see ConvertArg). It's in UTC and
+// fixed-width, so such times compare correctly as text. Like SQLite's own time functions, it only
+// keeps milliseconds.
+const SqliteTimeLayout = `2006-01-02T15:04:05.000Z`
+
+// sqliteTimeFormat is the strftime equivalent of SqliteTimeLayout.
+const sqliteTimeFormat = `%Y-%m-%dT%H:%M:%fZ`
+
+var sqliteExtractFormats = map[ast.TimeUnit]string{
+	ast.TimeUnitYear:   `%Y`,
+	ast.TimeUnitMonth:  `%m`,
+	ast.TimeUnitDay:    `%d`,
+	ast.TimeUnitHour:   `%H`,
+	ast.TimeUnitMinute: `%M`,
+	ast.TimeUnitSecond: `%S`,
+}
+
+var sqliteTruncFormats = map[ast.TimeUnit]string{
+	ast.TimeUnitYear:   `%Y-01-01T00:00:00.000Z`,
+	ast.TimeUnitMonth:  `%Y-%m-01T00:00:00.000Z`,
+	ast.TimeUnitDay:    `%Y-%m-%dT00:00:00.000Z`,
+	ast.TimeUnitHour:   `%Y-%m-%dT%H:00:00.000Z`,
+	ast.TimeUnitMinute: `%Y-%m-%dT%H:%M:00.000Z`,
+	ast.TimeUnitSecond: `%Y-%m-%dT%H:%M:%S.000Z`,
+}
+
+func (s Sqlite) formatBuiltin(w io.Writer, b *ast.Builtin) {
+	switch b.Func {
+	case ast.BuiltinLength:
+		fmt.Fprint(w, `LENGTH(`)
+		formatCommaDelimited(w, s, b.Args...)
+		fmt.Fprint(w, `)`)
+	case ast.BuiltinConcat:
+		// || binds more tightly than any other operator, so it's parenthesized as a whole and any
+		// operand which isn't atomic is parenthesized too.
+		fmt.Fprint(w, `(`)
+		for i, arg := range b.Args {
+			if i > 0 {
+				fmt.Fprint(w, ` || `)
+			}
+			if ast.Precedence(arg) < ast.PrecedenceAtom {
+				fmt.Fprint(w, `(`)
+				s.format(w, arg)
+				fmt.Fprint(w, `)`)
+			} else {
+				s.format(w, arg)
+			}
+		}
+		fmt.Fprint(w, `)`)
+	case ast.BuiltinNow:
+		fmt.Fprintf(w, `STRFTIME(%s,'now')`, s.quoteString(sqliteTimeFormat))
+	case ast.BuiltinExtract:
+		layout, ok := sqliteExtractFormats[b.Unit]
+		if !ok {
+			failf(``, b, `cannot extract unit %v from a time`, b.Unit)
+		}
+		fmt.Fprintf(w, `CAST(STRFTIME(%s,`, s.quoteString(layout))
+		formatCommaDelimited(w, s, b.Args...)
+		fmt.Fprint(w, `) AS INTEGER)`)
+	case ast.BuiltinTruncTime:
+		layout, ok := sqliteTruncFormats[b.Unit]
+		if !ok {
+			failf(``, b, `cannot truncate a time to unit %v`, b.Unit)
+		}
+		fmt.Fprintf(w, `STRFTIME(%s,`, s.quoteString(layout))
+		formatCommaDelimited(w, s, b.Args...)
+		fmt.Fprint(w, `)`)
+	default:
+		failf(``, b, `unsupported function: %v`, b.Func)
+	}
+}
+
+func (s Sqlite) formatGroupConcat(w io.Writer, g *ast.GroupConcat) {
+	fmt.Fprint(w, `GROUP_CONCAT(`)
+	if g.Distinct {
+		// SQLite only allows DISTINCT in aggregates with a single argument, so the separator can't be
+		// given. Luckily the default is a comma.
+		if g.Separator != `,` {
+			failf(``, g, `sqlite does not support GROUP_CONCAT with DISTINCT and a separator other than ','`)
+		}
+		fmt.Fprint(w, `DISTINCT `)
+		s.format(w, g.Arg)
+	} else {
+		s.format(w, g.Arg)
+		fmt.Fprintf(w, `,%s`, s.quoteString(g.Separator))
+	}
+	if g.OrderBy != nil {
+		fmt.Fprint(w, ` `)
+		s.format(w, g.OrderBy)
 	}
 	fmt.Fprint(w, `)`)
 }
 
+func (s Sqlite) formatRaw(w io.Writer, raw *ast.Raw) {
+	frags := raw.Fragments(scan.Sqlite)
+	if len(frags)-1 != len(raw.Args) {
+		failf(`raw SQL`, raw, `%d placeholders but %d arguments`, len(frags)-1, len(raw.Args))
+	}
+	for i, frag := range frags {
+		fmt.Fprint(w, frag)
+		if i < len(raw.Args) {
+			s.format(w, raw.Args[i])
+		}
+	}
+}
+
+func (s Sqlite) formatCase(w io.Writer, c *ast.Case) {
+	fmt.Fprint(w, `CASE`)
+	if c.Operand != nil {
+		fmt.Fprint(w, ` `)
+		s.format(w, c.Operand)
+	}
+	for _, when := range c.Whens {
+		fmt.Fprint(w, ` WHEN `)
+		s.format(w, when.Cond)
+		fmt.Fprint(w, ` THEN `)
+		s.format(w, when.Result)
+	}
+	if c.Else != nil {
+		fmt.Fprint(w, ` ELSE `)
+		s.format(w, c.Else)
+	}
+	fmt.Fprint(w, ` END`)
+}
+
+func (s Sqlite) formatWindowFunc(w io.Writer, f *ast.WindowFunc) {
+	s.format(w, f.Func)
+	fmt.Fprint(w, ` OVER `)
+	if f.Window == nil {
+		fmt.Fprint(w, f.WindowName)
+		return
+	}
+	fmt.Fprint(w, `(`)
+	s.format(w, f.Window)
+	fmt.Fprint(w, `)`)
+}
+
+func (s Sqlite) formatWindowSpec(w io.Writer, spec *ast.WindowSpec) {
+	sep := ``
+	if len(spec.PartitionBy) > 0 {
+		fmt.Fprint(w, `PARTITION BY `)
+		formatCommaDelimited(w, s, spec.PartitionBy...)
+		sep = ` `
+	}
+	if spec.OrderBy != nil {
+		fmt.Fprint(w, sep)
+		s.format(w, spec.OrderBy)
+		sep = ` `
+	}
+	if spec.Frame != nil {
+		fmt.Fprint(w, sep)
+		formatWindowFrame(w, spec.Frame)
+	}
+}
+
 func (s Sqlite) formatIntegerLiteral(w io.Writer, l *ast.IntegerLiteral) {
 	fmt.Fprintf(w, `%d`, l.Value)
 }
 
 func (s Sqlite) formatStringLiteral(w io.Writer, l *ast.StringLiteral) {
-	fmt.Fprintf(w, `'%s'`, l.Value)
+	fmt.Fprint(w, s.quoteString(l.Value))
+}
+
+// quoteString quotes str as a SQLite string literal, in which the only special character is the quote.
+func (s Sqlite) quoteString(str string) string {
+	return `'` + strings.ReplaceAll(str, `'`, `''`) + `'`
 }
 
 func (s Sqlite) formatNullLiteral(w io.Writer, _ *ast.NullLiteral) {
 	fmt.Fprint(w, `NULL`)
 }
 
+// formatBoolLiteral uses 1 and 0, since TRUE and FALSE are only keywords as of SQLite 3.23.
+func (s Sqlite) formatBoolLiteral(w io.Writer, l *ast.BoolLiteral) {
+	if l.Value {
+		fmt.Fprint(w, `1`)
+	} else {
+		fmt.Fprint(w, `0`)
+	}
+}
+
+func (s Sqlite) formatTimeLiteral(w io.Writer, l *ast.TimeLiteral) {
+	fmt.Fprintf(w, `'%s'`, l.Value.UTC().Format(SqliteTimeLayout))
+}
+
 func (s Sqlite) formatTupleLiteral(w io.Writer, t *ast.TupleLiteral) {
 	fmt.Fprint(w, `(`)
 	for i, val := range t.Values {
-		s.FormatNode(w, val)
+		s.format(w, val)
 		if i < len(t.Values)-1 {
 			fmt.Fprint(w, `,`)
 		}
@@ -262,13 +680,13 @@ func (s Sqlite) formatPlaceholderLiteral(w io.Writer, _ *ast.PlaceholderLiteral)
 
 func (s Sqlite) formatWhere(w io.Writer, wh *ast.Where) {
 	fmt.Fprint(w, `WHERE `)
-	s.FormatNode(w, wh.Expr)
+	s.format(w, wh.Expr)
 }
 
 func (s Sqlite) formatOrderBy(w io.Writer, o *ast.OrderBy) {
 	fmt.Fprint(w, `ORDER BY `)
 	for i, ord := range o.Orders {
-		s.FormatNode(w, ord.Expr)
+		s.format(w, ord.Expr)
 		switch ord.Direction {
 		case ast.OrderAsc:
 			fmt.Fprint(w, ` ASC`)
@@ -289,10 +707,10 @@ func (s Sqlite) formatLock(w io.Writer, l *ast.Lock) {
 func (s Sqlite) formatLimit(w io.Writer, l *ast.Limit) {
 	fmt.Fprint(w, `LIMIT `)
 	if l.Offset != nil {
-		s.FormatNode(w, l.Offset)
+		s.format(w, l.Offset)
 		fmt.Fprint(w, `, `)
 	}
-	s.FormatNode(w, l.Count)
+	s.format(w, l.Count)
 }
 
 func (s Sqlite) formatIdentifier(w io.Writer, c *ast.Identifier) {
@@ -300,24 +718,24 @@ func (s Sqlite) formatIdentifier(w io.Writer, c *ast.Identifier) {
 }
 
 func (s Sqlite) formatSelector(w io.Writer, sel *ast.Selector) {
-	s.FormatNode(w, sel.SelectFrom)
+	s.format(w, sel.SelectFrom)
 	fmt.Fprint(w, ".")
-	s.FormatNode(w, sel.FieldName)
+	s.format(w, sel.FieldName)
 }
 
 func (s Sqlite) formatValuesLiteral(w io.Writer, vl *ast.ValuesLiteral) {
-	s.FormatNode(w, &ast.Selector{
+	s.format(w, &ast.Selector{
 		SelectFrom: ast.NewIdentifier("excluded"),
 		FieldName:  vl.Target,
 	})
 }
 
 func (s Sqlite) formatTableName(w io.Writer, tn *ast.TableName) {
-	s.FormatNode(w, tn.Identifier)
+	s.format(w, tn.Identifier)
 }
 
 func (s Sqlite) formatJoin(w io.Writer, j *ast.Join) {
-	s.FormatNode(w, j.Left)
+	s.format(w, j.Left)
 
 	switch j.Kind {
 	case ast.JoinKindInner:
@@ -325,30 +743,30 @@ func (s Sqlite) formatJoin(w io.Writer, j *ast.Join) {
 	case ast.JoinKindLeft:
 		fmt.Fprint(w, ` LEFT JOIN `)
 	default:
-		panic(`unexpected join kind`)
+		failf(`JOIN`, j, `unexpected join kind: %v`, j.Kind)
 	}
 
-	s.FormatNode(w, j.Right)
+	s.format(w, j.Right)
 	fmt.Fprint(w, ` ON `)
-	s.FormatNode(w, j.On)
+	s.format(w, j.On)
 }
 
 func (s Sqlite) formatAlias(w io.Writer, a *ast.Alias) {
-	s.FormatNode(w, a.ForExpr)
+	s.format(w, a.ForExpr)
 	fmt.Fprint(w, ` AS `)
-	s.FormatNode(w, a.As)
+	s.format(w, a.As)
 }
 
 func (s Sqlite) formatTableAlias(w io.Writer, a *ast.TableAlias) {
-	s.FormatNode(w, a.ForExpr)
+	s.format(w, a.ForExpr)
 	fmt.Fprint(w, ` AS `)
-	s.FormatNode(w, a.As)
+	s.format(w, a.As)
 }
 
 func (s Sqlite) formatUnaryExpr(w io.Writer, un *ast.UnaryExpr) {
 	// For postfix operators, we format the operand before the operator.
 	if un.Op.IsPost() {
-		s.FormatNode(w, un.Operand)
+		s.formatOperand(w, un, un.Operand, false)
 	}
 
 	switch un.Op {
@@ -356,18 +774,39 @@ func (s Sqlite) formatUnaryExpr(w io.Writer, un *ast.UnaryExpr) {
 		fmt.Fprint(w, " IS NOT NULL")
 	case ast.UnaryIsNull:
 		fmt.Fprint(w, " IS NULL")
+	case ast.UnaryNot:
+		// NOT binds more loosely than comparisons but more tightly than AND and OR, so its operand
+		// is always parenthesized.
+		fmt.Fprint(w, "NOT (")
 	default:
-		panic(fmt.Sprintf("unexpected ast.UnaryExprOperator: %#v", un.Op))
+		failf(``, un, `unsupported unary operation: %v`, un.Op)
 	}
 
 	// For prefix operators, we format the operand after the operator.
 	if !un.Op.IsPost() {
-		s.FormatNode(w, un.Operand)
+		s.format(w, un.Operand)
+	}
+
+	if un.Op == ast.UnaryNot {
+		fmt.Fprint(w, ")")
 	}
 }
 
 func (s Sqlite) formatBinaryExpr(w io.Writer, bin *ast.BinaryExpr) {
-	s.FormatNode(w, bin.Left)
+
+	// SQLite can only compare a row value against a list of rows coming from a subquery.
+	if e, ok := expandRowIn(bin); ok {
+		if ast.Precedence(e) < ast.Precedence(bin) {
+			fmt.Fprint(w, `(`)
+			s.format(w, e)
+			fmt.Fprint(w, `)`)
+		} else {
+			s.format(w, e)
+		}
+		return
+	}
+
+	s.formatOperand(w, bin, bin.Left, false)
 
 	switch bin.Op {
 	case ast.BinaryEquals:
@@ -388,11 +827,56 @@ func (s Sqlite) formatBinaryExpr(w io.Writer, bin *ast.BinaryExpr) {
 		fmt.Fprint(w, ` AND `)
 	case ast.BinaryOr:
 		fmt.Fprint(w, ` OR `)
+	case ast.BinaryNotIn:
+		fmt.Fprint(w, ` NOT IN `)
+	case ast.BinaryLike:
+		fmt.Fprint(w, ` LIKE `)
+	case ast.BinaryNotLike:
+		fmt.Fprint(w, ` NOT LIKE `)
+	default:
+		failf(``, bin, `unsupported binary operation: %v`, bin.Op)
+	}
+
+	s.formatOperand(w, bin, bin.Right, true)
+}
+
+// formatOperand formats an operand of parent, parenthesizing it if it binds more loosely than parent.
+func (s Sqlite) formatOperand(w io.Writer, parent, operand ast.Expr, right bool) {
+	if !ast.NeedsParens(parent, operand, right) {
+		s.format(w, operand)
+		return
+	}
+
+	fmt.Fprint(w, `(`)
+	s.format(w, operand)
+	fmt.Fprint(w, `)`)
+}
+
+func (s Sqlite) formatTernaryExpr(w io.Writer, t *ast.TernaryExpr) {
+	s.formatOperand(w, t, t.First, false)
+
+	switch t.Op {
+	case ast.TernaryBetween:
+		fmt.Fprint(w, ` BETWEEN `)
+		s.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` AND `)
+	case ast.TernaryNotBetween:
+		fmt.Fprint(w, ` NOT BETWEEN `)
+		s.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` AND `)
+	case ast.TernaryLikeEscape:
+		fmt.Fprint(w, ` LIKE `)
+		s.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` ESCAPE `)
+	case ast.TernaryNotLikeEscape:
+		fmt.Fprint(w, ` NOT LIKE `)
+		s.formatOperand(w, t, t.Second, true)
+		fmt.Fprint(w, ` ESCAPE `)
 	default:
-		panic(fmt.Sprintf(`unsupported binary operation: %v`, bin.Op))
+		failf(``, t, `unsupported ternary operation: %v`, t.Op)
 	}
 
-	s.FormatNode(w, bin.Right)
+	s.formatOperand(w, t, t.Third, true)
 }
 
 func (s Sqlite) formatDistinct(w io.Writer, d *ast.Distinct) {
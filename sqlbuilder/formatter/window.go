@@ -0,0 +1,40 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+// formatWindowFrame formats a window frame, which is spelled the same way by every dialect we support.
+func formatWindowFrame(w io.Writer, f *ast.WindowFrame) {
+	switch f.Unit {
+	case ast.FrameRows:
+		fmt.Fprint(w, `ROWS BETWEEN `)
+	case ast.FrameRange:
+		fmt.Fprint(w, `RANGE BETWEEN `)
+	default:
+		failf(`OVER`, nil, `unsupported window frame unit: %v`, f.Unit)
+	}
+	formatFrameBound(w, f.Start)
+	fmt.Fprint(w, ` AND `)
+	formatFrameBound(w, f.End)
+}
+
+func formatFrameBound(w io.Writer, b ast.FrameBound) {
+	switch b.Kind {
+	case ast.BoundUnboundedPreceding:
+		fmt.Fprint(w, `UNBOUNDED PRECEDING`)
+	case ast.BoundPreceding:
+		fmt.Fprintf(w, `%d PRECEDING`, b.Offset)
+	case ast.BoundCurrentRow:
+		fmt.Fprint(w, `CURRENT ROW`)
+	case ast.BoundFollowing:
+		fmt.Fprintf(w, `%d FOLLOWING`, b.Offset)
+	case ast.BoundUnboundedFollowing:
+		fmt.Fprint(w, `UNBOUNDED FOLLOWING`)
+	default:
+		failf(`OVER`, nil, `unsupported window frame bound: %v`, b.Kind)
+	}
+}
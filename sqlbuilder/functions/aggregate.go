@@ -0,0 +1,73 @@
+package functions
+
+import (
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/filter"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+func Sum(e ast.IntoExpr) Call {
+	return call(`SUM`, e)
+}
+
+func Avg(e ast.IntoExpr) Call {
+	return call(`AVG`, e)
+}
+
+func Min(e ast.IntoExpr) Call {
+	return call(`MIN`, e)
+}
+
+func Max(e ast.IntoExpr) Call {
+	return call(`MAX`, e)
+}
+
+type GroupConcatCall struct {
+	arg       ast.IntoExpr
+	distinct  bool
+	separator string
+	orders    []filter.Order
+}
+
+// GroupConcat concatenates the values of e in each group, separated by commas unless Separator is
+// given. This is GROUP_CONCAT in MySQL and SQLite, and STRING_AGG in most other databases.
+func GroupConcat(e ast.IntoExpr) GroupConcatCall {
+	return GroupConcatCall{
+		arg:       e,
+		separator: `,`,
+	}
+}
+
+// Distinct only concatenates distinct values. SQLite doesn't allow this with a custom separator.
+func (g GroupConcatCall) Distinct() GroupConcatCall {
+	g.distinct = true
+	return g
+}
+
+func (g GroupConcatCall) Separator(sep string) GroupConcatCall {
+	g.separator = sep
+	return g
+}
+
+// OrderBy sets the order in which values are concatenated. It can be called multiple times to order by
+// several expressions.
+func (g GroupConcatCall) OrderBy(o filter.Order) GroupConcatCall {
+	g.orders = append(g.orders[:len(g.orders):len(g.orders)], o)
+	return g
+}
+
+func (g GroupConcatCall) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(g, alias)
+}
+
+func (g GroupConcatCall) IntoExpr() ast.Expr {
+	gc := ast.NewGroupConcat(g.arg)
+	gc.Distinct = g.distinct
+	gc.Separator = g.separator
+	if len(g.orders) > 0 {
+		gc.OrderBy = &ast.OrderBy{}
+		for _, o := range g.orders {
+			gc.OrderBy.Orders = append(gc.OrderBy.Orders, ast.NewOrder(o.ToASTExpr(), o.Direction.ToASTDirection()))
+		}
+	}
+	return gc
+}
@@ -0,0 +1,27 @@
+package functions
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+
+// Call is a function call. It can be selected, compared against (e.g. with filter.ExprEquals) or passed
+// as an argument to other functions. Arguments are any expression: columns (column.Named("x")),
+// placeholders (filter.Value(1)) or other calls.
+type Call struct {
+	expr ast.Expr
+}
+
+func (c Call) IntoExpr() ast.Expr {
+	return c.expr
+}
+
+// As aliases the call, e.g. for SELECT COUNT(*) AS n. ORDER BY and HAVING can refer to the alias by name.
+func (c Call) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(c, alias)
+}
+
+func call(name string, args ...ast.IntoExpr) Call {
+	return Call{expr: ast.NewFunction(name, args...)}
+}
+
+func builtin(fn ast.BuiltinFunc, args ...ast.IntoExpr) Call {
+	return Call{expr: ast.NewBuiltin(fn, args...)}
+}
@@ -1,6 +1,9 @@
 package functions
 
-import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+import (
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/window"
+)
 
 type Count struct {
 	Arg      ast.IntoExpr
@@ -33,6 +36,10 @@ func (c Count) All() bool {
 	return c.Arg == nil
 }
 
+func (c Count) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(c, alias)
+}
+
 func (c Count) IntoExpr() ast.Expr {
 	if c.All() {
 		return ast.NewFunction(`COUNT`, ast.NewStarLiteral())
@@ -44,3 +51,13 @@ func (c Count) IntoExpr() ast.Expr {
 
 	return ast.NewFunction(`COUNT`, c.Arg)
 }
+
+// Over evaluates the count as a window function over w.
+func (c Count) Over(w *window.Builder) Call {
+	return Call{expr: ast.NewWindowFunc(c, w.IntoSpec())}
+}
+
+// OverWindow evaluates the count as a window function over a window declared with sel.Builder.Window.
+func (c Count) OverWindow(name string) Call {
+	return Call{expr: ast.NewNamedWindowFunc(c, name)}
+}
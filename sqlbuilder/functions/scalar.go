@@ -0,0 +1,55 @@
+package functions
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+
+// Coalesce returns the first of es which isn't NULL.
+func Coalesce(es ...ast.IntoExpr) Call {
+	return call(`COALESCE`, es...)
+}
+
+// IfNull returns e, or fallback if e is NULL.
+func IfNull(e, fallback ast.IntoExpr) Call {
+	return call(`IFNULL`, e, fallback)
+}
+
+// NullIf returns NULL if a equals b, and a otherwise.
+func NullIf(a, b ast.IntoExpr) Call {
+	return call(`NULLIF`, a, b)
+}
+
+func Lower(e ast.IntoExpr) Call {
+	return call(`LOWER`, e)
+}
+
+func Upper(e ast.IntoExpr) Call {
+	return call(`UPPER`, e)
+}
+
+// Length returns the number of characters in e (CHAR_LENGTH in MySQL, where LENGTH counts bytes).
+func Length(e ast.IntoExpr) Call {
+	return builtin(ast.BuiltinLength, e)
+}
+
+// Substr returns length characters of e starting at start, which is 1-based.
+func Substr(e ast.IntoExpr, start, length int) Call {
+	return call(`SUBSTR`, e, ast.NewIntegerLiteral(start), ast.NewIntegerLiteral(length))
+}
+
+// SubstrFrom returns the characters of e from start, which is 1-based, to the end.
+func SubstrFrom(e ast.IntoExpr, start int) Call {
+	return call(`SUBSTR`, e, ast.NewIntegerLiteral(start))
+}
+
+// Concat concatenates strings (CONCAT in MySQL, || in SQLite).
+func Concat(es ...ast.IntoExpr) Call {
+	return builtin(ast.BuiltinConcat, es...)
+}
+
+func Abs(e ast.IntoExpr) Call {
+	return call(`ABS`, e)
+}
+
+// Round rounds e to the given number of decimal places.
+func Round(e ast.IntoExpr, digits int) Call {
+	return call(`ROUND`, e, ast.NewIntegerLiteral(digits))
+}
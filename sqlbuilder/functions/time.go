@@ -0,0 +1,61 @@
+package functions
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+
+type Timestamp struct{}
+
+// CurrentTimestamp returns the CURRENT_TIMESTAMP expression. It can be selected, compared against,
+// or used as a column default. In SQLite it's the same as Now.
+func CurrentTimestamp() Timestamp {
+	return Timestamp{}
+}
+
+func (Timestamp) IntoExpr() ast.Expr {
+	return ast.NewCurrentTimestampLiteral()
+}
+
+type TimeUnit int
+
+const (
+	Year TimeUnit = iota
+	Month
+	Day
+	Hour
+	Minute
+	Second
+)
+
+func (u TimeUnit) ToASTTimeUnit() ast.TimeUnit {
+	switch u {
+	case Year:
+		return ast.TimeUnitYear
+	case Month:
+		return ast.TimeUnitMonth
+	case Day:
+		return ast.TimeUnitDay
+	case Hour:
+		return ast.TimeUnitHour
+	case Minute:
+		return ast.TimeUnitMinute
+	case Second:
+		return ast.TimeUnitSecond
+	}
+	return ast.TimeUnitInvalid
+}
+
+// Now returns the current date and time. In SQLite, it's formatted with formatter.SqliteTimeLayout, like
+// time literals and the time.Time arguments the builders bind, so that it compares with them as text.
+func Now() Call {
+	return builtin(ast.BuiltinNow)
+}
+
+// Extract returns the given part of the time e as an integer, e.g. Extract(Month, column.Named("t")).
+func Extract(unit TimeUnit, e ast.IntoExpr) Call {
+	return Call{expr: ast.NewBuiltin(ast.BuiltinExtract, e).WithUnit(unit.ToASTTimeUnit())}
+}
+
+// TruncTime truncates the time e to the start of the given unit, e.g. TruncTime(Month, t) is midnight
+// on the first of t's month.
+func TruncTime(unit TimeUnit, e ast.IntoExpr) Call {
+	return Call{expr: ast.NewBuiltin(ast.BuiltinTruncTime, e).WithUnit(unit.ToASTTimeUnit())}
+}
@@ -0,0 +1,48 @@
+package functions
+
+import (
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/window"
+)
+
+// RowNumber numbers the rows of each partition from 1. It must be used with Over.
+func RowNumber() Call {
+	return call(`ROW_NUMBER`)
+}
+
+// Rank ranks the rows of each partition by its order, with gaps after ties. It must be used with Over.
+func Rank() Call {
+	return call(`RANK`)
+}
+
+// DenseRank is like Rank, but without gaps after ties. It must be used with Over.
+func DenseRank() Call {
+	return call(`DENSE_RANK`)
+}
+
+// Lag returns e from offset rows before the current row in its partition, or NULL if there's no such
+// row. It must be used with Over.
+func Lag(e ast.IntoExpr, offset int) Call {
+	return call(`LAG`, e, ast.NewIntegerLiteral(offset))
+}
+
+// Lead returns e from offset rows after the current row in its partition, or NULL if there's no such
+// row. It must be used with Over.
+func Lead(e ast.IntoExpr, offset int) Call {
+	return call(`LEAD`, e, ast.NewIntegerLiteral(offset))
+}
+
+// FirstValue returns e from the first row of the window. It must be used with Over.
+func FirstValue(e ast.IntoExpr) Call {
+	return call(`FIRST_VALUE`, e)
+}
+
+// Over evaluates c as a window function over w. Aggregates (e.g. Sum) can be used this way too.
+func (c Call) Over(w *window.Builder) Call {
+	return Call{expr: ast.NewWindowFunc(c, w.IntoSpec())}
+}
+
+// OverWindow evaluates c as a window function over a window declared with sel.Builder.Window.
+func (c Call) OverWindow(name string) Call {
+	return Call{expr: ast.NewNamedWindowFunc(c, name)}
+}
@@ -0,0 +1,126 @@
+// Package hooks provides hooks for logging and measuring the statements builders run. Add them with
+// sqlbuilder.Builder.AddHooks, or a single builder's WithHooks.
+package hooks
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/dispatch"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/statement"
+)
+
+// Slog logs every statement to l at the given level, or at slog.LevelError if it failed. Arguments
+// aren't logged, since they may be sensitive.
+func Slog(l *slog.Logger, level slog.Level) dispatch.Hook {
+	return func(ctx context.Context, e *dispatch.Event, next func(context.Context) error) error {
+		err := next(ctx)
+
+		attrs := []slog.Attr{
+			slog.String(`method`, e.Method),
+			slog.String(`stmt`, e.Stmt),
+			slog.Duration(`duration`, e.Duration),
+		}
+		if e.RowsAffected >= 0 {
+			attrs = append(attrs, slog.Int64(`rows_affected`, e.RowsAffected))
+		}
+
+		lvl := level
+		if err != nil {
+			lvl = slog.LevelError
+			attrs = append(attrs, slog.String(`error`, err.Error()))
+		}
+		l.LogAttrs(ctx, lvl, `sql`, attrs...)
+		return err
+	}
+}
+
+// Slow calls h only for statements which took at least threshold, e.g.
+//
+//	hooks.Slow(time.Second, hooks.Slog(logger, slog.LevelWarn))
+//
+// Since that's only known once the statement has run, h is called afterwards, and its next returns the
+// statement's error rather than running it again.
+func Slow(threshold time.Duration, h dispatch.Hook) dispatch.Hook {
+	return func(ctx context.Context, e *dispatch.Event, next func(context.Context) error) error {
+		err := next(ctx)
+		if e.Duration < threshold {
+			return err
+		}
+		return h(ctx, e, func(context.Context) error { return err })
+	}
+}
+
+// Stats are the totals for one kind of statement.
+type Stats struct {
+	// Query is the statement's normalized form (see statement.Fingerprint).
+	Query        string
+	Count        int64
+	Errors       int64
+	Duration     time.Duration
+	RowsAffected int64
+}
+
+// Counter counts statements, grouping them by their fingerprint so that e.g. inserts of different
+// numbers of rows count as the same statement. It's safe for concurrent use.
+type Counter struct {
+	mu    sync.Mutex
+	stats map[string]*Stats
+}
+
+// Hook returns the hook which feeds the counter.
+func (c *Counter) Hook() dispatch.Hook {
+	return func(ctx context.Context, e *dispatch.Event, next func(context.Context) error) error {
+		err := next(ctx)
+		fp := statement.Statement{Stmt: e.Stmt}.Fingerprint()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.stats == nil {
+			c.stats = make(map[string]*Stats)
+		}
+		s, ok := c.stats[fp.Hash]
+		if !ok {
+			s = &Stats{Query: fp.Normalized}
+			c.stats[fp.Hash] = s
+		}
+
+		s.Count++
+		s.Duration += e.Duration
+		if err != nil {
+			s.Errors++
+		}
+		if e.RowsAffected > 0 {
+			s.RowsAffected += e.RowsAffected
+		}
+		return err
+	}
+}
+
+// Stats returns the totals so far, ordered by query.
+func (c *Counter) Stats() []Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := make([]Stats, 0, len(c.stats))
+	for _, s := range c.stats {
+		res = append(res, *s)
+	}
+	slices.SortFunc(res, func(a, b Stats) int {
+		return strings.Compare(a.Query, b.Query)
+	})
+	return res
+}
+
+// Reset clears the totals.
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats = nil
+}
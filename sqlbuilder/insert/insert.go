@@ -3,8 +3,9 @@ package insert
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/conflict"
@@ -14,7 +15,7 @@ import (
 )
 
 type Formatter interface {
-	FormatNode(w io.Writer, n ast.Node)
+	FormatNode(w io.Writer, n ast.Node) error
 }
 
 type Builder struct {
@@ -23,6 +24,16 @@ type Builder struct {
 	columns   []string
 	args      []any
 	conflicts *conflictData
+
+	generatedColumns []string
+
+	hooks []dispatch.Hook
+}
+
+// generatedColumner is implemented by table references which know their schema (e.g.
+// table.Named("foo").WithColumns(...)).
+type generatedColumner interface {
+	GeneratedColumns() []string
 }
 
 type conflictData struct {
@@ -31,10 +42,14 @@ type conflictData struct {
 }
 
 func NewBuilder(f Formatter, table ast.IntoTableExpr) *Builder {
-	return &Builder{
+	b := &Builder{
 		f:     f,
 		table: table,
 	}
+	if gc, ok := table.(generatedColumner); ok {
+		b.generatedColumns = gc.GeneratedColumns()
+	}
+	return b
 }
 
 func (b *Builder) Columns(cols ...string) *Builder {
@@ -78,16 +93,22 @@ func (b *Builder) OverwriteConflicts(key conflict.Key) *Builder {
 }
 
 func (b *Builder) Build() (statement.Statement, error) {
+	if err := b.validateGeneratedColumns(); err != nil {
+		return statement.Statement{}, err
+	}
 	return build(b.f, b.table, b.conflicts, b.columns, b.args)
 }
 
 func (b *Builder) BuildBatchesOfSize(itemsPerBatch int) ([]statement.Statement, error) {
 	if itemsPerBatch <= 0 {
-		return nil, errors.New(`batch size must be greater than 0`)
+		return nil, &ast.BuildError{Reason: `batch size must be greater than 0`}
 	}
 	if err := validate(b.columns, b.args); err != nil {
 		return nil, err
 	}
+	if err := b.validateGeneratedColumns(); err != nil {
+		return nil, err
+	}
 
 	numArgsPerItem := len(b.columns)
 	numItems := len(b.args) / numArgsPerItem
@@ -155,28 +176,56 @@ func build(f Formatter, table ast.IntoTableExpr, conflicts *conflictData, column
 	}
 
 	sb := strings.Builder{}
-	f.FormatNode(&sb, ins)
+	if err := f.FormatNode(&sb, ins); err != nil {
+		return statement.Statement{}, err
+	}
 
 	return statement.Statement{
 		Stmt: sb.String(),
-		Args: ast.GetArgs(ins),
+		Args: ast.GetArgsFor(f, ins),
 	}, nil
 }
 
 func validate(columns []string, args []any) error {
 	if len(columns) == 0 {
-		return errors.New(`must provide columns to insert`)
+		return &ast.BuildError{Clause: `INSERT`, Reason: `must provide columns to insert`}
 	}
 	if len(args)%len(columns) != 0 {
-		return errors.New(`number of arguments must be divisible by the number of columns`)
+		return &ast.BuildError{Clause: `VALUES`, Reason: `number of arguments must be divisible by the number of columns`}
 	}
 	return nil
 }
 
+// validateGeneratedColumns rejects writes to generated columns, which both MySQL and SQLite refuse.
+// This is only possible when the table reference carries its schema. Column names are compared
+// case-insensitively, like both databases compare them.
+func (b *Builder) validateGeneratedColumns() error {
+	for _, gen := range b.generatedColumns {
+		if slices.ContainsFunc(b.columns, func(c string) bool { return strings.EqualFold(c, gen) }) {
+			return &ast.BuildError{Clause: `INSERT`, Reason: fmt.Sprintf(`cannot insert into generated column %q`, gen)}
+		}
+		if b.conflicts == nil {
+			continue
+		}
+		for _, c := range b.conflicts.conflictBehaviors {
+			if strings.EqualFold(c.Field(), gen) {
+				return &ast.BuildError{Clause: `INSERT`, Reason: fmt.Sprintf(`cannot update generated column %q on conflict`, gen)}
+			}
+		}
+	}
+	return nil
+}
+
+// WithHooks adds hooks, which wrap each statement the builder runs.
+func (b *Builder) WithHooks(hs ...dispatch.Hook) *Builder {
+	b.hooks = append(b.hooks, hs...)
+	return b
+}
+
 func (b *Builder) Exec(e dispatch.Execer) (sql.Result, error) {
-	return dispatch.Exec(b, e)
+	return dispatch.Exec(b, e, b.hooks...)
 }
 
 func (b *Builder) ExecContext(ctx context.Context, e dispatch.ExecCtxer) (sql.Result, error) {
-	return dispatch.ExecContext(ctx, b, e)
+	return dispatch.ExecContext(ctx, b, e, b.hooks...)
 }
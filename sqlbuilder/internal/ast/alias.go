@@ -6,10 +6,19 @@ type Alias struct {
 	As      *Identifier
 }
 
+func NewAlias(expr IntoExpr, as string) *Alias {
+	return &Alias{
+		ForExpr: expr.IntoExpr(),
+		As:      NewIdentifier(as),
+	}
+}
+
 func (a *Alias) IntoExpr() Expr {
 	return a
 }
 
 func (a *Alias) AcceptVisitor(fn func(Node) bool) {
-	fn(a)
+	if fn(a) {
+		a.ForExpr.AcceptVisitor(fn)
+	}
 }
@@ -1,5 +1,11 @@
 package ast
 
+// ArgConverter is implemented by formatters which need some arguments converted before they're bound,
+// e.g. to store times the same way the formatter writes them.
+type ArgConverter interface {
+	ConvertArg(v any) any
+}
+
 func GetArgs(n Node) []any {
 	var args []any
 
@@ -15,3 +21,16 @@ func GetArgs(n Node) []any {
 
 	return args
 }
+
+// GetArgsFor returns n's arguments, converted by f if it's an ArgConverter.
+func GetArgsFor(f any, n Node) []any {
+	args := GetArgs(n)
+	c, ok := f.(ArgConverter)
+	if !ok {
+		return args
+	}
+	for i, a := range args {
+		args[i] = c.ConvertArg(a)
+	}
+	return args
+}
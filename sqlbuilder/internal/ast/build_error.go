@@ -0,0 +1,19 @@
+package ast
+
+// BuildError explains why a statement can't be built: either the AST is invalid, or it uses something
+// the formatter's dialect doesn't support.
+type BuildError struct {
+	// Clause is the part of the statement the problem is in, e.g. SET or CREATE TABLE.
+	Clause string
+	// Node is the offending node. It's nil if the problem isn't with any one node.
+	Node Node
+	// Reason describes the problem.
+	Reason string
+}
+
+func (e *BuildError) Error() string {
+	if e.Clause == `` {
+		return e.Reason
+	}
+	return e.Clause + `: ` + e.Reason
+}
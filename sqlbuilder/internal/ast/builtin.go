@@ -0,0 +1,129 @@
+package ast
+
+// BuiltinFunc is a function whose name or syntax differs between dialects. Formatters translate these,
+// whereas a Function is printed by name.
+type BuiltinFunc int
+
+const (
+	// BuiltinLength is the number of characters (not bytes) in a string.
+	BuiltinLength BuiltinFunc = iota
+	// BuiltinConcat concatenates strings.
+	BuiltinConcat
+	// BuiltinNow is the current date and time.
+	BuiltinNow
+	// BuiltinExtract extracts a part of a date and time as an integer.
+	BuiltinExtract
+	// BuiltinTruncTime truncates a date and time to the start of a unit.
+	BuiltinTruncTime
+)
+
+func (f BuiltinFunc) String() string {
+	switch f {
+	case BuiltinLength:
+		return `LENGTH`
+	case BuiltinConcat:
+		return `CONCAT`
+	case BuiltinNow:
+		return `NOW`
+	case BuiltinExtract:
+		return `EXTRACT`
+	case BuiltinTruncTime:
+		return `TRUNC`
+	}
+	return `UNKNOWN`
+}
+
+type TimeUnit int
+
+const (
+	TimeUnitYear TimeUnit = iota
+	TimeUnitMonth
+	TimeUnitDay
+	TimeUnitHour
+	TimeUnitMinute
+	TimeUnitSecond
+
+	// TimeUnitInvalid is what unknown units are converted to. Validate rejects it.
+	TimeUnitInvalid TimeUnit = -1
+)
+
+func (u TimeUnit) String() string {
+	switch u {
+	case TimeUnitYear:
+		return `YEAR`
+	case TimeUnitMonth:
+		return `MONTH`
+	case TimeUnitDay:
+		return `DAY`
+	case TimeUnitHour:
+		return `HOUR`
+	case TimeUnitMinute:
+		return `MINUTE`
+	case TimeUnitSecond:
+		return `SECOND`
+	}
+	return `UNKNOWN`
+}
+
+type Builtin struct {
+	Expr
+	Func BuiltinFunc
+	Args []Expr
+
+	// Unit is used by BuiltinExtract and BuiltinTruncTime.
+	Unit TimeUnit
+}
+
+func NewBuiltin(fn BuiltinFunc, args ...IntoExpr) *Builtin {
+	return &Builtin{
+		Func: fn,
+		Args: IntoExprs(args...),
+	}
+}
+
+func (b *Builtin) WithUnit(u TimeUnit) *Builtin {
+	b.Unit = u
+	return b
+}
+
+func (b *Builtin) IntoExpr() Expr {
+	return b
+}
+
+func (b *Builtin) AcceptVisitor(fn func(Node) bool) {
+	if fn(b) {
+		for _, a := range b.Args {
+			a.AcceptVisitor(fn)
+		}
+	}
+}
+
+// GroupConcat is the aggregate which concatenates the values of a group, called GROUP_CONCAT in MySQL
+// and SQLite and STRING_AGG elsewhere.
+type GroupConcat struct {
+	Expr
+	Arg       Expr
+	Distinct  bool
+	Separator string
+	OrderBy   *OrderBy
+}
+
+func NewGroupConcat(arg IntoExpr) *GroupConcat {
+	return &GroupConcat{
+		Arg:       arg.IntoExpr(),
+		Separator: `,`,
+	}
+}
+
+func (g *GroupConcat) IntoExpr() Expr {
+	return g
+}
+
+func (g *GroupConcat) AcceptVisitor(fn func(Node) bool) {
+	if fn(g) {
+		g.Arg.AcceptVisitor(fn)
+		if g.OrderBy != nil {
+			g.OrderBy.AcceptVisitor(fn)
+		}
+	}
+}
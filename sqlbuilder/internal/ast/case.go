@@ -0,0 +1,55 @@
+package ast
+
+// Case is a CASE expression. With an Operand, it's the simple form (CASE x WHEN 1 THEN ...), which
+// compares the operand with each When's Cond. Without one, each Cond is a boolean condition.
+type Case struct {
+	Expr
+	Operand Expr
+	Whens   []When
+	Else    Expr
+}
+
+type When struct {
+	Cond   Expr
+	Result Expr
+}
+
+func NewCase(operand IntoExpr) *Case {
+	c := &Case{}
+	if operand != nil {
+		c.Operand = operand.IntoExpr()
+	}
+	return c
+}
+
+func (c *Case) AddWhen(cond, result IntoExpr) *Case {
+	c.Whens = append(c.Whens, When{
+		Cond:   cond.IntoExpr(),
+		Result: result.IntoExpr(),
+	})
+	return c
+}
+
+func (c *Case) WithElse(e IntoExpr) *Case {
+	c.Else = e.IntoExpr()
+	return c
+}
+
+func (c *Case) IntoExpr() Expr {
+	return c
+}
+
+func (c *Case) AcceptVisitor(fn func(Node) bool) {
+	if fn(c) {
+		if c.Operand != nil {
+			c.Operand.AcceptVisitor(fn)
+		}
+		for _, w := range c.Whens {
+			w.Cond.AcceptVisitor(fn)
+			w.Result.AcceptVisitor(fn)
+		}
+		if c.Else != nil {
+			c.Else.AcceptVisitor(fn)
+		}
+	}
+}
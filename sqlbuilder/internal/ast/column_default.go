@@ -11,5 +11,18 @@ func newColumnDefault(val Expr) *ColumnDefault {
 }
 
 func (c *ColumnDefault) AcceptVisitor(fn func(n Node) bool) {
-	fn(c)
+	if fn(c) {
+		c.Value.AcceptVisitor(fn)
+	}
+}
+
+// IsLiteral reports whether the default value can be written as-is. Both MySQL and SQLite require
+// any other expression to be wrapped in parentheses, e.g. DEFAULT (UUID()).
+func (c *ColumnDefault) IsLiteral() bool {
+	switch c.Value.(type) {
+	case *IntegerLiteral, *StringLiteral, *NullLiteral, *TimeLiteral, *CurrentTimestampLiteral:
+		return true
+	default:
+		return false
+	}
 }
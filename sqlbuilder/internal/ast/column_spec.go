@@ -5,9 +5,13 @@ type ColumnSpec struct {
 
 	Name             *Identifier
 	Type             ColumnType
+	Generated        *GeneratedColumn
 	Nullability      Nullability
 	Default          *ColumnDefault
+	OnUpdate         Expr
 	AutoIncrementing *AutoIncrement
+	Collation        string
+	Comment          string
 
 	ComprisesPrimaryKey bool
 }
@@ -24,12 +28,18 @@ func (cs *ColumnSpec) AcceptVisitor(fn func(n Node) bool) {
 	if fn(cs) {
 		cs.Name.AcceptVisitor(fn)
 		cs.Type.AcceptVisitor(fn)
+		if cs.Generated != nil {
+			cs.Generated.AcceptVisitor(fn)
+		}
 		if cs.Nullability != NoNullability {
 			cs.Nullability.AcceptVisitor(fn)
 		}
 		if cs.Default != nil {
 			cs.Default.AcceptVisitor(fn)
 		}
+		if cs.OnUpdate != nil {
+			cs.OnUpdate.AcceptVisitor(fn)
+		}
 		if cs.AutoIncrementing != nil {
 			cs.AutoIncrementing.AcceptVisitor(fn)
 		}
@@ -62,6 +72,26 @@ func (c *ColumnSpec) WithDefault(val IntoExpr) *ColumnSpec {
 	return c
 }
 
+func (c *ColumnSpec) WithGenerated(expr IntoExpr, stored bool) *ColumnSpec {
+	c.Generated = NewGeneratedColumn(expr, stored)
+	return c
+}
+
+func (c *ColumnSpec) WithOnUpdate(val IntoExpr) *ColumnSpec {
+	c.OnUpdate = val.IntoExpr()
+	return c
+}
+
+func (c *ColumnSpec) WithCollation(collation string) *ColumnSpec {
+	c.Collation = collation
+	return c
+}
+
+func (c *ColumnSpec) WithComment(comment string) *ColumnSpec {
+	c.Comment = comment
+	return c
+}
+
 func (c *ColumnSpec) SetPrimaryKey(val bool) *ColumnSpec {
 	c.ComprisesPrimaryKey = val
 	return c
@@ -27,6 +27,7 @@ type (
 	MediumBlobColumn struct{ ColumnType }
 	LongBlobColumn   struct{ ColumnType }
 	DateTimeColumn   struct{ ColumnType }
+	TimestampColumn  struct{ ColumnType }
 )
 
 func TinyInt() TinyIntColumn {
@@ -130,3 +131,11 @@ func DateTime() DateTimeColumn {
 func (c DateTimeColumn) AcceptVisitor(fn func(n Node) bool) {
 	fn(c)
 }
+
+func Timestamp() TimestampColumn {
+	return TimestampColumn{}
+}
+
+func (c TimestampColumn) AcceptVisitor(fn func(n Node) bool) {
+	fn(c)
+}
@@ -6,6 +6,16 @@ type CreateTable struct {
 
 	Columns    []*ColumnSpec
 	PrimaryKey *PrimaryKey
+	Options    []*TableOption
+
+	// AsSelect creates the table from the result of a query (CREATE TABLE ... AS SELECT).
+	AsSelect *Select
+
+	// Like creates the table with the same definition as another table (CREATE TABLE ... LIKE).
+	// LikeDefinition is the other table's CREATE TABLE statement, for dialects which have to emulate
+	// LIKE by rewriting it.
+	Like           *TableName
+	LikeDefinition string
 }
 
 func NewCreateTable(name string) *CreateTable {
@@ -20,6 +30,15 @@ func (c *CreateTable) AcceptVisitor(fn func(n Node) bool) {
 		for _, col := range c.Columns {
 			col.AcceptVisitor(fn)
 		}
+		for _, opt := range c.Options {
+			opt.AcceptVisitor(fn)
+		}
+		if c.AsSelect != nil {
+			c.AsSelect.AcceptVisitor(fn)
+		}
+		if c.Like != nil {
+			c.Like.AcceptVisitor(fn)
+		}
 	}
 }
 
@@ -34,6 +53,19 @@ func (c *CreateTable) AddColumn(cs *ColumnSpec) {
 	}
 }
 
+func (c *CreateTable) AddOption(opt *TableOption) {
+	c.Options = append(c.Options, opt)
+}
+
+func (c *CreateTable) HasOption(kind TableOptionKind) bool {
+	for _, opt := range c.Options {
+		if opt.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *CreateTable) addPrimaryKeyColumn(colName string) {
 	if c.PrimaryKey == nil {
 		c.PrimaryKey = NewPrimaryKey()
@@ -17,8 +17,8 @@ func (s *Delete) AcceptVisitor(fn func(n Node) bool) {
 	if fn(s) {
 		s.From.AcceptVisitor(fn)
 		s.Where.AcceptVisitor(fn)
-		s.Limit.AcceptVisitor(fn)
 		s.OrderBy.AcceptVisitor(fn)
+		s.Limit.AcceptVisitor(fn)
 	}
 }
 
@@ -14,7 +14,7 @@ func NewDistinct(exprs ...IntoExpr) *Distinct {
 func (d *Distinct) AcceptVisitor(fn func(Node) bool) {
 	if fn(d) {
 		for _, expr := range d.Exprs {
-			fn(expr)
+			expr.AcceptVisitor(fn)
 		}
 	}
 }
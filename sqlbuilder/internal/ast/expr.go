@@ -32,6 +32,7 @@ type UnaryExprOperator int
 const (
 	UnaryIsNull    UnaryExprOperator = iota
 	UnaryIsNotNull UnaryExprOperator = iota
+	UnaryNot
 )
 
 func (op UnaryExprOperator) IsPost() bool {
@@ -80,6 +81,9 @@ const (
 	BinaryIn
 	BinaryAnd
 	BinaryOr
+	BinaryNotIn
+	BinaryLike
+	BinaryNotLike
 )
 
 type BinaryExpr struct {
@@ -107,3 +111,45 @@ func (b *BinaryExpr) AcceptVisitor(fn func(Node) bool) {
 		b.Right.AcceptVisitor(fn)
 	}
 }
+
+type TernaryExprOperator int
+
+const (
+	// TernaryBetween is First BETWEEN Second AND Third
+	TernaryBetween TernaryExprOperator = iota
+	// TernaryNotBetween is First NOT BETWEEN Second AND Third
+	TernaryNotBetween
+	// TernaryLikeEscape is First LIKE Second ESCAPE Third
+	TernaryLikeEscape
+	// TernaryNotLikeEscape is First NOT LIKE Second ESCAPE Third
+	TernaryNotLikeEscape
+)
+
+type TernaryExpr struct {
+	Expr
+	Op     TernaryExprOperator
+	First  Expr
+	Second Expr
+	Third  Expr
+}
+
+func NewTernaryExpr(first IntoExpr, op TernaryExprOperator, second, third IntoExpr) *TernaryExpr {
+	return &TernaryExpr{
+		Op:     op,
+		First:  first.IntoExpr(),
+		Second: second.IntoExpr(),
+		Third:  third.IntoExpr(),
+	}
+}
+
+func (t *TernaryExpr) IntoExpr() Expr {
+	return t
+}
+
+func (t *TernaryExpr) AcceptVisitor(fn func(Node) bool) {
+	if fn(t) {
+		t.First.AcceptVisitor(fn)
+		t.Second.AcceptVisitor(fn)
+		t.Third.AcceptVisitor(fn)
+	}
+}
@@ -13,6 +13,10 @@ func NewFunction(name string, args ...IntoExpr) *Function {
 	}
 }
 
+func (f *Function) IntoExpr() Expr {
+	return f
+}
+
 func (f *Function) AcceptVisitor(fn func(Node) bool) {
 	if fn(f) {
 		for _, a := range f.Args {
@@ -0,0 +1,21 @@
+package ast
+
+// GeneratedColumn is the GENERATED ALWAYS AS (expr) clause of a column definition. Stored columns are
+// computed on write and take up space; virtual columns are computed on read.
+type GeneratedColumn struct {
+	Expr   Expr
+	Stored bool
+}
+
+func NewGeneratedColumn(expr IntoExpr, stored bool) *GeneratedColumn {
+	return &GeneratedColumn{
+		Expr:   expr.IntoExpr(),
+		Stored: stored,
+	}
+}
+
+func (g *GeneratedColumn) AcceptVisitor(fn func(n Node) bool) {
+	if fn(g) {
+		g.Expr.AcceptVisitor(fn)
+	}
+}
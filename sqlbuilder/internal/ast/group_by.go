@@ -0,0 +1,29 @@
+package ast
+
+type GroupBy struct {
+	Exprs []Expr
+}
+
+func (g *GroupBy) AcceptVisitor(fn func(Node) bool) {
+	if g == nil {
+		return
+	}
+	if fn(g) {
+		for _, e := range g.Exprs {
+			e.AcceptVisitor(fn)
+		}
+	}
+}
+
+type Having struct {
+	Expr Expr
+}
+
+func (h *Having) AcceptVisitor(fn func(Node) bool) {
+	if h == nil {
+		return
+	}
+	if fn(h) {
+		h.Expr.AcceptVisitor(fn)
+	}
+}
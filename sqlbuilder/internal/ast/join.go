@@ -39,5 +39,9 @@ func (t *Join) IntoTableExpr() TableExpr {
 }
 
 func (t *Join) AcceptVisitor(fn func(Node) bool) {
-	fn(t)
+	if fn(t) {
+		t.Left.AcceptVisitor(fn)
+		t.Right.AcceptVisitor(fn)
+		t.On.AcceptVisitor(fn)
+	}
 }
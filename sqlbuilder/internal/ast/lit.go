@@ -1,5 +1,7 @@
 package ast
 
+import "time"
+
 type PlaceholderLiteral struct {
 	Expr
 	For any
@@ -77,6 +79,27 @@ func (l *NullLiteral) AcceptVisitor(fn func(Node) bool) {
 	fn(l)
 }
 
+// BoolLiteral is a constant truth value. Filters which always (or never) match, like an empty All or
+// an empty IN, are represented with it.
+type BoolLiteral struct {
+	Expr
+	Value bool
+}
+
+func NewBoolLiteral(val bool) *BoolLiteral {
+	return &BoolLiteral{
+		Value: val,
+	}
+}
+
+func (l *BoolLiteral) IntoExpr() Expr {
+	return l
+}
+
+func (l *BoolLiteral) AcceptVisitor(fn func(Node) bool) {
+	fn(l)
+}
+
 type StarLiteral struct {
 	Expr
 }
@@ -92,3 +115,41 @@ func (l *StarLiteral) IntoExpr() Expr {
 func (l *StarLiteral) AcceptVisitor(fn func(Node) bool) {
 	fn(l)
 }
+
+type TimeLiteral struct {
+	Expr
+	Value time.Time
+}
+
+func NewTimeLiteral(val time.Time) *TimeLiteral {
+	return &TimeLiteral{
+		Value: val,
+	}
+}
+
+func (l *TimeLiteral) IntoExpr() Expr {
+	return l
+}
+
+func (l *TimeLiteral) AcceptVisitor(fn func(Node) bool) {
+	fn(l)
+}
+
+// CurrentTimestampLiteral is the CURRENT_TIMESTAMP keyword. It is a literal rather than a Function
+// because it's spelled without parentheses and is allowed in places a function call isn't (e.g. an
+// unparenthesized column DEFAULT or MySQL's ON UPDATE).
+type CurrentTimestampLiteral struct {
+	Expr
+}
+
+func NewCurrentTimestampLiteral() *CurrentTimestampLiteral {
+	return &CurrentTimestampLiteral{}
+}
+
+func (l *CurrentTimestampLiteral) IntoExpr() Expr {
+	return l
+}
+
+func (l *CurrentTimestampLiteral) AcceptVisitor(fn func(Node) bool) {
+	fn(l)
+}
@@ -37,6 +37,10 @@ func NewValuesLiteral(target *Identifier) *ValuesLiteral {
 	}
 }
 
+func (vl *ValuesLiteral) IntoExpr() Expr {
+	return vl
+}
+
 func (vl *ValuesLiteral) AcceptVisitor(fn func(n Node) bool) {
 	if fn(vl) {
 		vl.Target.AcceptVisitor(fn)
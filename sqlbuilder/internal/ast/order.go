@@ -5,6 +5,9 @@ type OrderDirection int
 const (
 	OrderAsc OrderDirection = iota
 	OrderDesc
+
+	// OrderInvalid is what unknown directions are converted to. Validate rejects it.
+	OrderInvalid OrderDirection = -1
 )
 
 type Order struct {
@@ -24,5 +27,12 @@ type OrderBy struct {
 }
 
 func (o *OrderBy) AcceptVisitor(fn func(Node) bool) {
-	fn(o)
+	if o == nil {
+		return
+	}
+	if fn(o) {
+		for _, ord := range o.Orders {
+			ord.Expr.AcceptVisitor(fn)
+		}
+	}
 }
@@ -0,0 +1,63 @@
+package ast
+
+// Operator precedence levels, from loosest to tightest binding. MySQL and SQLite rank the comparison
+// operators differently among themselves (e.g. SQLite binds < tighter than =), so they share a level
+// here, and a comparison used as an operand of another one is always parenthesized.
+const (
+	// PrecedenceUnknown is for raw SQL, which could contain anything, so it's parenthesized whenever
+	// it's used as an operand.
+	PrecedenceUnknown = iota
+	PrecedenceOr
+	PrecedenceAnd
+	PrecedenceNot
+	PrecedenceComparison
+	// PrecedenceAtom is for anything which can't be split by a surrounding operator (identifiers,
+	// literals, function calls, parenthesized lists...)
+	PrecedenceAtom
+)
+
+// Precedence returns how tightly e binds to its operands. When an operand has a lower precedence than
+// the operator using it, it has to be parenthesized to keep its meaning.
+func Precedence(e Expr) int {
+	switch e := e.(type) {
+	case *BinaryExpr:
+		switch e.Op {
+		case BinaryOr:
+			return PrecedenceOr
+		case BinaryAnd:
+			return PrecedenceAnd
+		default:
+			return PrecedenceComparison
+		}
+	case *UnaryExpr:
+		if e.Op == UnaryNot {
+			return PrecedenceNot
+		}
+		return PrecedenceComparison
+	case *TernaryExpr:
+		return PrecedenceComparison
+	case *Raw:
+		return PrecedenceUnknown
+	default:
+		return PrecedenceAtom
+	}
+}
+
+// NeedsParens reports whether operand has to be parenthesized when used as an operand of parent. right
+// says whether operand is on the right-hand side of parent. Operands of equal precedence are grouped
+// left-to-right, so they only need parentheses on the right, unless the operator is associative (AND,
+// OR). Comparisons are the exception: they're always parenthesized inside one another.
+func NeedsParens(parent, operand Expr, right bool) bool {
+	pp, op := Precedence(parent), Precedence(operand)
+	if op == PrecedenceComparison && pp == PrecedenceComparison {
+		return true
+	}
+	if op != pp || !right {
+		return op < pp
+	}
+
+	pb, ok1 := parent.(*BinaryExpr)
+	ob, ok2 := operand.(*BinaryExpr)
+	associative := ok1 && ok2 && pb.Op == ob.Op && (pb.Op == BinaryAnd || pb.Op == BinaryOr)
+	return !associative
+}
@@ -0,0 +1,45 @@
+package ast
+
+import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/scan"
+
+// Raw is a fragment of SQL written by hand. Formatters split its ? markers out into placeholders, so
+// that they can render them in their own dialect, and GetArgs finds its arguments in the right place.
+// Raw can be used as an expression or as a table expression.
+type Raw struct {
+	TableExpr
+
+	SQL  string
+	Args []*PlaceholderLiteral
+}
+
+// NewRaw returns sql with the arguments for its ? markers. If the number of markers doesn't match the
+// number of args, formatters report the mismatch.
+func NewRaw(sql string, args ...any) *Raw {
+	r := &Raw{SQL: sql}
+	for _, a := range args {
+		r.Args = append(r.Args, NewPlaceholderLiteral(a))
+	}
+	return r
+}
+
+// Fragments returns the pieces of SQL between the ? markers which aren't inside strings, quoted
+// identifiers or comments in dialect d, so there's always one more fragment than there are markers.
+func (r *Raw) Fragments(d scan.Dialect) []string {
+	return scan.SplitPlaceholders(r.SQL, d)
+}
+
+func (r *Raw) IntoExpr() Expr {
+	return r
+}
+
+func (r *Raw) IntoTableExpr() TableExpr {
+	return r
+}
+
+func (r *Raw) AcceptVisitor(fn func(Node) bool) {
+	if fn(r) {
+		for _, a := range r.Args {
+			a.AcceptVisitor(fn)
+		}
+	}
+}
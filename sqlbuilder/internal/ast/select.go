@@ -4,6 +4,9 @@ type Select struct {
 	From    TableExpr
 	Exprs   []Expr
 	Where   *Where
+	GroupBy *GroupBy
+	Having  *Having
+	Windows []NamedWindow
 	Limit   *Limit
 	OrderBy *OrderBy
 	Lock    *Lock
@@ -20,15 +23,22 @@ func NewSelect(from TableExpr, exprs ...IntoExpr) *Select {
 	}
 }
 
+// AcceptVisitor visits the clauses in the order they're written, since that's the order their
+// placeholders' arguments are bound in.
 func (s *Select) AcceptVisitor(fn func(n Node) bool) {
 	if fn(s) {
-		s.From.AcceptVisitor(fn)
 		for _, exp := range s.Exprs {
 			exp.AcceptVisitor(fn)
 		}
+		s.From.AcceptVisitor(fn)
 		s.Where.AcceptVisitor(fn)
-		s.Limit.AcceptVisitor(fn)
+		s.GroupBy.AcceptVisitor(fn)
+		s.Having.AcceptVisitor(fn)
+		for _, w := range s.Windows {
+			w.Spec.AcceptVisitor(fn)
+		}
 		s.OrderBy.AcceptVisitor(fn)
+		s.Limit.AcceptVisitor(fn)
 		s.Lock.AcceptVisitor(fn)
 	}
 }
@@ -45,6 +55,36 @@ func (s *Select) WithWhere(expr IntoExpr) *Select {
 	return s
 }
 
+func (s *Select) WithGroupBy(exprs ...IntoExpr) *Select {
+	if len(exprs) == 0 {
+		return s
+	}
+	s.GroupBy = &GroupBy{
+		Exprs: IntoExprs(exprs...),
+	}
+	return s
+}
+
+func (s *Select) WithHaving(expr IntoExpr) *Select {
+	e := expr.IntoExpr()
+	if e == nil {
+		return s
+	}
+
+	s.Having = &Having{
+		Expr: e,
+	}
+	return s
+}
+
+func (s *Select) WithWindow(name string, spec *WindowSpec) *Select {
+	s.Windows = append(s.Windows, NamedWindow{
+		Name: name,
+		Spec: spec,
+	})
+	return s
+}
+
 func (s *Select) WithOrders(os ...Order) *Select {
 	if s.OrderBy == nil {
 		s.OrderBy = &OrderBy{
@@ -0,0 +1,24 @@
+package ast
+
+// Subquery is a SELECT used as an expression, e.g. on the right-hand side of IN. It is always formatted
+// in parentheses.
+type Subquery struct {
+	Expr
+	Select *Select
+}
+
+func NewSubquery(s *Select) *Subquery {
+	return &Subquery{
+		Select: s,
+	}
+}
+
+func (s *Subquery) IntoExpr() Expr {
+	return s
+}
+
+func (s *Subquery) AcceptVisitor(fn func(Node) bool) {
+	if fn(s) {
+		s.Select.AcceptVisitor(fn)
+	}
+}
@@ -13,5 +13,7 @@ func (a *TableAlias) IntoTableExpr() TableExpr {
 }
 
 func (a *TableAlias) AcceptVisitor(fn func(Node) bool) {
-	fn(a)
+	if fn(a) {
+		a.ForExpr.AcceptVisitor(fn)
+	}
 }
@@ -0,0 +1,49 @@
+package ast
+
+type TableOptionKind int
+
+const (
+	TableOptionEngine TableOptionKind = iota
+	TableOptionCharset
+	TableOptionCollate
+	TableOptionComment
+	TableOptionWithoutRowID
+	TableOptionStrict
+)
+
+func (k TableOptionKind) String() string {
+	switch k {
+	case TableOptionEngine:
+		return `ENGINE`
+	case TableOptionCharset:
+		return `DEFAULT CHARSET`
+	case TableOptionCollate:
+		return `COLLATE`
+	case TableOptionComment:
+		return `COMMENT`
+	case TableOptionWithoutRowID:
+		return `WITHOUT ROWID`
+	case TableOptionStrict:
+		return `STRICT`
+	default:
+		return `UNKNOWN`
+	}
+}
+
+// TableOption is anything that follows the column list of a CREATE TABLE. Options are dialect
+// specific; each formatter emits the ones its dialect supports and rejects the others.
+type TableOption struct {
+	Kind  TableOptionKind
+	Value string
+}
+
+func NewTableOption(kind TableOptionKind, val string) *TableOption {
+	return &TableOption{
+		Kind:  kind,
+		Value: val,
+	}
+}
+
+func (o *TableOption) AcceptVisitor(fn func(n Node) bool) {
+	fn(o)
+}
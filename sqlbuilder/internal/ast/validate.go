@@ -0,0 +1,168 @@
+package ast
+
+import "fmt"
+
+// Validate checks n and everything under it for mistakes which would otherwise render invalid SQL,
+// like a SELECT without any columns. It returns the first problem found as a *BuildError. Problems
+// which only affect some dialects are left to the formatters.
+func Validate(n Node) error {
+	var err *BuildError
+	n.AcceptVisitor(func(n Node) bool {
+		if err == nil {
+			err = validateNode(n)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func invalid(clause string, n Node, format string, args ...any) *BuildError {
+	return &BuildError{
+		Clause: clause,
+		Node:   n,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+func validateNode(n Node) *BuildError {
+	switch n := n.(type) {
+	case *Select:
+		if n.From == nil {
+			return invalid(`FROM`, n, `no table to select from`)
+		}
+		if len(n.Exprs) == 0 {
+			return invalid(`SELECT`, n, `no columns or expressions to select`)
+		}
+	case *Distinct:
+		if len(n.Exprs) == 0 {
+			return invalid(`DISTINCT`, n, `no columns or expressions to select`)
+		}
+	case *Insert:
+		if len(n.Columns) == 0 {
+			return invalid(`INSERT`, n, `no columns to insert`)
+		}
+		if len(n.Values) == 0 {
+			return invalid(`VALUES`, n, `no values to insert`)
+		}
+		for _, row := range n.Values {
+			if len(row.Values) != len(n.Columns) {
+				return invalid(`VALUES`, row, `%d values for %d columns`, len(row.Values), len(n.Columns))
+			}
+		}
+	case *Update:
+		if len(n.AssignmentList) == 0 {
+			return invalid(`SET`, n, `no columns to update`)
+		}
+	case *CreateTable:
+		switch {
+		case n.Like != nil && n.AsSelect != nil:
+			return invalid(`CREATE TABLE`, n, `cannot create a table both LIKE another table and AS SELECT`)
+		case n.Like != nil && (len(n.Columns) > 0 || len(n.Options) > 0):
+			return invalid(`CREATE TABLE`, n, `cannot specify columns or table options when creating a table LIKE another table`)
+		case n.Like == nil && n.AsSelect == nil && len(n.Columns) == 0:
+			return invalid(`CREATE TABLE`, n, `table %s has no columns`, n.Name.Name)
+		}
+	case *ColumnSpec:
+		if n.Collation != `` && !isName(n.Collation) {
+			return invalid(`COLLATE`, n, `invalid collation %q for column %s`, n.Collation, n.Name.Name)
+		}
+	case *TableOption:
+		switch n.Kind {
+		case TableOptionEngine, TableOptionCharset, TableOptionCollate:
+			if !isName(n.Value) {
+				return invalid(`CREATE TABLE`, n, `invalid %s %q`, n.Kind, n.Value)
+			}
+		}
+	case *ColumnDefault:
+		if hasPlaceholder(n.Value) {
+			return invalid(`DEFAULT`, n, `a column default cannot contain placeholders`)
+		}
+	case *GeneratedColumn:
+		if hasPlaceholder(n.Expr) {
+			return invalid(`GENERATED`, n, `a generated column's expression cannot contain placeholders`)
+		}
+	case *Builtin:
+		if (n.Func == BuiltinExtract || n.Func == BuiltinTruncTime) && (n.Unit < TimeUnitYear || n.Unit > TimeUnitSecond) {
+			return invalid(n.Func.String(), n, `unknown time unit %d`, n.Unit)
+		}
+	case *OrderBy:
+		for _, o := range n.Orders {
+			if o.Direction != OrderAsc && o.Direction != OrderDesc {
+				return invalid(`ORDER BY`, n, `unknown direction %d`, o.Direction)
+			}
+		}
+	case *Case:
+		if len(n.Whens) == 0 {
+			return invalid(`CASE`, n, `must have at least one WHEN`)
+		}
+	case *BinaryExpr:
+		return validateRowValues(n)
+	}
+	return nil
+}
+
+// isName reports whether s is a plain name, like the engines, character sets and collations which are
+// written into DDL unquoted.
+func isName(s string) bool {
+	if s == `` {
+		return false
+	}
+	for _, c := range s {
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPlaceholder reports whether e binds any arguments. DDL can't take arguments, so the statement would
+// have markers which the database can't bind.
+func hasPlaceholder(e Expr) bool {
+	found := false
+	e.AcceptVisitor(func(n Node) bool {
+		switch n := n.(type) {
+		case *PlaceholderLiteral:
+			found = true
+		case *Raw:
+			found = found || len(n.Args) > 0
+		}
+		return !found
+	})
+	return found
+}
+
+// validateRowValues fails if bin compares row values of different sizes, like (a, b) = (?, ?, ?) or
+// (a, b) IN ((?, ?), (?)), or compares with an empty list. The databases reject these too, but with much less helpful errors.
+func validateRowValues(bin *BinaryExpr) *BuildError {
+	if right, ok := bin.Right.(*TupleLiteral); ok && len(right.Values) == 0 {
+		return invalid(`row value`, bin, `cannot compare with an empty list`)
+	}
+
+	left, ok := bin.Left.(*TupleLiteral)
+	if !ok {
+		return nil
+	}
+	if len(left.Values) == 0 {
+		return invalid(`row value`, bin, `must have at least one column`)
+	}
+
+	rows := []Expr{bin.Right}
+	if bin.Op == BinaryIn || bin.Op == BinaryNotIn {
+		right, ok := bin.Right.(*TupleLiteral)
+		if !ok {
+			return nil
+		}
+		rows = right.Values
+	}
+
+	for _, r := range rows {
+		row, ok := r.(*TupleLiteral)
+		if ok && len(row.Values) != len(left.Values) {
+			return invalid(`row value`, bin, `cannot compare a row of %d values with a row of %d values`, len(left.Values), len(row.Values))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package ast
+
+type CreateView struct {
+	Name        *Identifier
+	OrReplace   bool
+	IfNotExists bool
+	Columns     []*Identifier
+	As          *Select
+}
+
+func NewCreateView(name string, as *Select) *CreateView {
+	return &CreateView{
+		Name: NewIdentifier(name),
+		As:   as,
+	}
+}
+
+func (c *CreateView) AcceptVisitor(fn func(n Node) bool) {
+	if fn(c) {
+		c.Name.AcceptVisitor(fn)
+		for _, col := range c.Columns {
+			col.AcceptVisitor(fn)
+		}
+		c.As.AcceptVisitor(fn)
+	}
+}
+
+type DropView struct {
+	Name     *Identifier
+	IfExists bool
+}
+
+func NewDropView(name string) *DropView {
+	return &DropView{
+		Name: NewIdentifier(name),
+	}
+}
+
+func (d *DropView) AcceptVisitor(fn func(n Node) bool) {
+	if fn(d) {
+		d.Name.AcceptVisitor(fn)
+	}
+}
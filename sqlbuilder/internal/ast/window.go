@@ -0,0 +1,91 @@
+package ast
+
+// WindowFunc is a function evaluated over a window of rows: either an inline window (fn OVER (...)) or
+// a window defined in the SELECT's WINDOW clause (fn OVER name).
+type WindowFunc struct {
+	Expr
+	Func       Expr
+	Window     *WindowSpec
+	WindowName string
+}
+
+func NewWindowFunc(fn IntoExpr, spec *WindowSpec) *WindowFunc {
+	return &WindowFunc{
+		Func:   fn.IntoExpr(),
+		Window: spec,
+	}
+}
+
+func NewNamedWindowFunc(fn IntoExpr, name string) *WindowFunc {
+	return &WindowFunc{
+		Func:       fn.IntoExpr(),
+		WindowName: name,
+	}
+}
+
+func (f *WindowFunc) IntoExpr() Expr {
+	return f
+}
+
+func (f *WindowFunc) AcceptVisitor(fn func(Node) bool) {
+	if fn(f) {
+		f.Func.AcceptVisitor(fn)
+		f.Window.AcceptVisitor(fn)
+	}
+}
+
+// WindowSpec is what goes in the parentheses after OVER, or after AS in a WINDOW clause.
+type WindowSpec struct {
+	PartitionBy []Expr
+	OrderBy     *OrderBy
+	Frame       *WindowFrame
+}
+
+func (s *WindowSpec) AcceptVisitor(fn func(Node) bool) {
+	if s == nil {
+		return
+	}
+	if fn(s) {
+		for _, e := range s.PartitionBy {
+			e.AcceptVisitor(fn)
+		}
+		s.OrderBy.AcceptVisitor(fn)
+	}
+}
+
+type FrameUnit int
+
+const (
+	FrameRows FrameUnit = iota
+	FrameRange
+)
+
+type FrameBoundKind int
+
+const (
+	BoundUnboundedPreceding FrameBoundKind = iota
+	BoundPreceding
+	BoundCurrentRow
+	BoundFollowing
+	BoundUnboundedFollowing
+)
+
+type FrameBound struct {
+	Kind FrameBoundKind
+	// Offset is the number of rows (or the range) for BoundPreceding and BoundFollowing.
+	Offset int
+}
+
+// WindowFrame is the subset of a partition a window function sees, e.g. ROWS BETWEEN 1 PRECEDING AND
+// CURRENT ROW.
+type WindowFrame struct {
+	Unit  FrameUnit
+	Start FrameBound
+	End   FrameBound
+}
+
+// NamedWindow is a window in a SELECT's WINDOW clause.
+type NamedWindow struct {
+	Name string
+	Spec *WindowSpec
+}
@@ -31,6 +31,15 @@ func (b *ConditionBuilder[T]) Where(f filter.Filter) T {
 	return b.parent
 }
 
+// AndWhere adds fs to the condition, which must match alongside anything passed to Where or AndWhere
+// before.
+func (b *ConditionBuilder[T]) AndWhere(fs ...filter.Filter) T {
+	if b.f != nil {
+		fs = append([]filter.Filter{b.f}, fs...)
+	}
+	return b.Where(filter.All(fs...))
+}
+
 func (b *ConditionBuilder[T]) WhereAll(f ...filter.Filter) T {
 	return b.Where(filter.All(f...))
 }
@@ -43,5 +52,11 @@ func (b *ConditionBuilder[T]) IntoExpr() ast.Expr {
 	if b.f == nil {
 		return nil
 	}
-	return b.f.IntoExpr()
+
+	// A condition which is always true is the same as no condition at all.
+	e := b.f.IntoExpr()
+	if l, ok := e.(*ast.BoolLiteral); ok && l.Value {
+		return nil
+	}
+	return e
 }
@@ -9,24 +9,70 @@ type Execer interface {
 	Exec(stmt string, args ...any) (sql.Result, error)
 }
 
-func Exec(b builder, e Execer) (sql.Result, error) {
+// Exec runs the builder's statement. Hooks start from context.Background(); if e can take a context
+// (like *sql.DB and *sql.Tx can), the one they pass on reaches the driver.
+func Exec(b builder, e Execer, hooks ...Hook) (sql.Result, error) {
+	if ce, ok := e.(ExecCtxer); ok && len(hooks) > 0 {
+		return ExecContext(context.Background(), b, ce, hooks...)
+	}
+
 	res, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	return e.Exec(res.Stmt, res.Args...)
+	var r sql.Result
+	ev := newEvent(`Exec`, res.Stmt, res.Args)
+	err = runHooks(context.Background(), hooks, ev, func(context.Context) error {
+		var err error
+		r, err = e.Exec(res.Stmt, res.Args...)
+		setRowsAffected(ev, r, err)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
 type ExecCtxer interface {
 	ExecContext(ctx context.Context, stmt string, args ...any) (sql.Result, error)
 }
 
-func ExecContext(ctx context.Context, b builder, e ExecCtxer) (sql.Result, error) {
+func ExecContext(ctx context.Context, b builder, e ExecCtxer, hooks ...Hook) (sql.Result, error) {
 	res, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	return e.ExecContext(ctx, res.Stmt, res.Args...)
+	var r sql.Result
+	ev := newEvent(`Exec`, res.Stmt, res.Args)
+	err = runHooks(ctx, hooks, ev, func(ctx context.Context) error {
+		var err error
+		r, err = e.ExecContext(ctx, res.Stmt, res.Args...)
+		setRowsAffected(ev, r, err)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func newEvent(method, stmt string, args []any) *Event {
+	return &Event{
+		Method:       method,
+		Stmt:         stmt,
+		Args:         args,
+		RowsAffected: -1,
+	}
+}
+
+func setRowsAffected(e *Event, r sql.Result, err error) {
+	if err != nil {
+		return
+	}
+	if n, err := r.RowsAffected(); err == nil {
+		e.RowsAffected = n
+	}
 }
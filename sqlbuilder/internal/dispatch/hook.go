@@ -0,0 +1,59 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Event describes a statement a builder runs. Method, Stmt and Args are set before hooks are called;
+// the rest is filled in once the statement has run, i.e. when next returns.
+type Event struct {
+	// Method is the method the statement is run with: Exec, Query or QueryRow.
+	Method string
+
+	Stmt string
+	Args []any
+
+	Duration time.Duration
+	Err      error
+	// RowsAffected is the number of rows an Exec affected, or -1 if it isn't known.
+	RowsAffected int64
+}
+
+// Hook wraps every statement a builder runs. It calls next to run the statement (or the next hook),
+// and returns its error, so it can act before and after the statement, and pass a derived context on
+// to the driver, e.g. one carrying a tracing span:
+//
+//	func(ctx context.Context, e *dispatch.Event, next func(context.Context) error) error {
+//		ctx, span := tracer.Start(ctx, e.Method)
+//		defer span.End()
+//		return next(ctx)
+//	}
+//
+// A hook which doesn't call next has to return an error, which is returned in place of the
+// statement's. Statements which fail to build aren't run, so they don't reach hooks.
+type Hook func(ctx context.Context, e *Event, next func(ctx context.Context) error) error
+
+var errNotRun = errors.New(`sqlbuilder: a hook returned without running the statement`)
+
+// runHooks runs call through hooks, the first one outermost, and fills in e.
+func runHooks(ctx context.Context, hooks []Hook, e *Event, call func(ctx context.Context) error) error {
+	if len(hooks) == 0 {
+		start := time.Now()
+		err := call(ctx)
+		e.Duration = time.Since(start)
+		e.Err = err
+		return err
+	}
+
+	ran := false
+	err := hooks[0](ctx, e, func(ctx context.Context) error {
+		ran = true
+		return runHooks(ctx, hooks[1:], e, call)
+	})
+	if !ran && err == nil {
+		return errNotRun
+	}
+	return err
+}
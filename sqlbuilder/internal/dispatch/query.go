@@ -9,50 +9,106 @@ type Queryer interface {
 	Query(stmt string, args ...any) (*sql.Rows, error)
 }
 
-func Query(b builder, q Queryer) (*sql.Rows, error) {
+// Query runs the builder's query. Like Exec, hooks start from context.Background(), and the context
+// they pass on reaches the driver if q can take one.
+func Query(b builder, q Queryer, hooks ...Hook) (*sql.Rows, error) {
+	if cq, ok := q.(QueryCtxer); ok && len(hooks) > 0 {
+		return QueryContext(context.Background(), b, cq, hooks...)
+	}
+
 	res, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	return q.Query(res.Stmt, res.Args...)
+	var rows *sql.Rows
+	err = runHooks(context.Background(), hooks, newEvent(`Query`, res.Stmt, res.Args), func(context.Context) error {
+		var err error
+		rows, err = q.Query(res.Stmt, res.Args...)
+		return err
+	})
+	return queryResult(rows, err)
 }
 
 type QueryCtxer interface {
 	QueryContext(ctx context.Context, stmt string, args ...any) (*sql.Rows, error)
 }
 
-func QueryContext(ctx context.Context, b builder, q QueryCtxer) (*sql.Rows, error) {
+// QueryContext runs the builder's query. The event's duration only covers running it, not reading the
+// rows.
+func QueryContext(ctx context.Context, b builder, q QueryCtxer, hooks ...Hook) (*sql.Rows, error) {
 	res, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	return q.QueryContext(ctx, res.Stmt, res.Args...)
+	var rows *sql.Rows
+	err = runHooks(ctx, hooks, newEvent(`Query`, res.Stmt, res.Args), func(ctx context.Context) error {
+		var err error
+		rows, err = q.QueryContext(ctx, res.Stmt, res.Args...)
+		return err
+	})
+	return queryResult(rows, err)
+}
+
+// queryResult closes rows if a hook failed the query after it ran.
+func queryResult(rows *sql.Rows, err error) (*sql.Rows, error) {
+	if err != nil {
+		if rows != nil {
+			_ = rows.Close()
+		}
+		return nil, err
+	}
+	return rows, nil
 }
 
 type RowQueryer interface {
 	QueryRow(stmt string, args ...any) *sql.Row
 }
 
-func QueryRow(b builder, q RowQueryer) (*sql.Row, error) {
+// QueryRow runs the builder's query. Like Exec, hooks start from context.Background(), and the
+// context they pass on reaches the driver if q can take one.
+func QueryRow(b builder, q RowQueryer, hooks ...Hook) (*sql.Row, error) {
+	if cq, ok := q.(RowQueryCtxer); ok && len(hooks) > 0 {
+		return QueryRowContext(context.Background(), b, cq, hooks...)
+	}
+
 	res, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	return q.QueryRow(res.Stmt, res.Args...), nil
+	var row *sql.Row
+	err = runHooks(context.Background(), hooks, newEvent(`QueryRow`, res.Stmt, res.Args), func(context.Context) error {
+		row = q.QueryRow(res.Stmt, res.Args...)
+		return row.Err()
+	})
+	return rowResult(row, err)
 }
 
 type RowQueryCtxer interface {
 	QueryRowContext(ctx context.Context, stmt string, args ...any) *sql.Row
 }
 
-func QueryRowContext(ctx context.Context, b builder, q RowQueryCtxer) (*sql.Row, error) {
+func QueryRowContext(ctx context.Context, b builder, q RowQueryCtxer, hooks ...Hook) (*sql.Row, error) {
 	res, err := b.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	return q.QueryRowContext(ctx, res.Stmt, res.Args...), nil
+	var row *sql.Row
+	err = runHooks(ctx, hooks, newEvent(`QueryRow`, res.Stmt, res.Args), func(ctx context.Context) error {
+		row = q.QueryRowContext(ctx, res.Stmt, res.Args...)
+		return row.Err()
+	})
+	return rowResult(row, err)
+}
+
+// rowResult returns row, which reports its own error when it's scanned, unless a hook failed the query
+// with a different error.
+func rowResult(row *sql.Row, err error) (*sql.Row, error) {
+	if row != nil && err == row.Err() {
+		return row, nil
+	}
+	return nil, err
 }
@@ -0,0 +1,187 @@
+// Package scan splits SQL text into tokens, for the packages which have to find their way around SQL
+// without parsing it: raw SQL's placeholders, Pretty, fingerprints and the parser. It knows where each
+// dialect's strings, quoted identifiers and comments start and end, so that a ?, a parenthesis or a
+// keyword inside one of them is never mistaken for the real thing.
+package scan
+
+import "strings"
+
+// Dialect describes how a dialect quotes strings and identifiers and writes comments.
+type Dialect struct {
+	// Backslashes is whether a backslash escapes the next character in a string.
+	Backslashes bool
+	// DoubleQuotedStrings is whether "..." is a string rather than a quoted identifier.
+	DoubleQuotedStrings bool
+	// HashComments is whether # starts a comment which runs to the end of the line.
+	HashComments bool
+	// Brackets is whether [...] is a quoted identifier.
+	Brackets bool
+}
+
+var (
+	Mysql  = Dialect{Backslashes: true, DoubleQuotedStrings: true, HashComments: true}
+	Sqlite = Dialect{Brackets: true}
+	// Any is for SQL whose dialect isn't known. It takes both dialects' comments and quoted
+	// identifiers, and MySQL's backslash escapes, which only misreads strings ending in a backslash.
+	Any = Dialect{Backslashes: true, HashComments: true, Brackets: true}
+)
+
+type Kind int
+
+const (
+	Space Kind = iota
+	// LineComment runs to the end of the line, not including the line break.
+	LineComment
+	BlockComment
+	String
+	QuotedIdent
+	Placeholder
+	// Number is a numeric literal, e.g. 12 or 1.5.
+	Number
+	// Word is a keyword or an unquoted identifier.
+	Word
+	// Punct is an operator or punctuation, e.g. ( or <=, or any other character.
+	Punct
+)
+
+type Token struct {
+	Kind Kind
+	// Text is the token as written in the SQL, including any quotes.
+	Text string
+	Pos  int
+	// Unterminated is whether a string, quoted identifier or block comment runs to the end of the SQL
+	// without being closed.
+	Unterminated bool
+}
+
+// multiPunct are the operators longer than a character.
+var multiPunct = []string{`!=`, `<>`, `<=`, `>=`, `||`}
+
+// Tokens splits sql into tokens. Every byte of sql belongs to exactly one token, so joining their
+// texts gives sql back.
+func Tokens(sql string, d Dialect) []Token {
+	var toks []Token
+	for i := 0; i < len(sql); {
+		t := next(sql, i, d)
+		toks = append(toks, t)
+		i += len(t.Text)
+	}
+	return toks
+}
+
+func next(sql string, i int, d Dialect) Token {
+	c := sql[i]
+	rest := sql[i:]
+	tok := func(kind Kind, n int) Token {
+		return Token{Kind: kind, Text: rest[:n], Pos: i}
+	}
+
+	switch {
+	case isSpace(c):
+		n := 1
+		for n < len(rest) && isSpace(rest[n]) {
+			n++
+		}
+		return tok(Space, n)
+	case strings.HasPrefix(rest, `--`) || c == '#' && d.HashComments:
+		n := strings.IndexByte(rest, '\n')
+		if n < 0 {
+			n = len(rest)
+		}
+		return tok(LineComment, n)
+	case strings.HasPrefix(rest, `/*`):
+		n := strings.Index(rest[2:], `*/`)
+		if n < 0 {
+			t := tok(BlockComment, len(rest))
+			t.Unterminated = true
+			return t
+		}
+		return tok(BlockComment, n+4)
+	case c == '\'' || c == '"' && d.DoubleQuotedStrings:
+		return quoted(rest, i, String, c, d.Backslashes)
+	case c == '`' || c == '"':
+		return quoted(rest, i, QuotedIdent, c, false)
+	case c == '[' && d.Brackets:
+		n := strings.IndexByte(rest, ']')
+		if n < 0 {
+			t := tok(QuotedIdent, len(rest))
+			t.Unterminated = true
+			return t
+		}
+		return tok(QuotedIdent, n+1)
+	case c == '?':
+		return tok(Placeholder, 1)
+	case isDigit(c):
+		n := 1
+		for n < len(rest) && (isWordByte(rest[n]) || rest[n] == '.') {
+			n++
+		}
+		return tok(Number, n)
+	case isWordByte(c):
+		n := 1
+		for n < len(rest) && isWordByte(rest[n]) {
+			n++
+		}
+		return tok(Word, n)
+	}
+
+	for _, p := range multiPunct {
+		if strings.HasPrefix(rest, p) {
+			return tok(Punct, len(p))
+		}
+	}
+	return tok(Punct, 1)
+}
+
+// quoted reads the string or quoted identifier at the start of s, which is at pos in the SQL. A
+// doubled quote stands for the quote itself.
+func quoted(s string, pos int, kind Kind, quote byte, backslashes bool) Token {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case backslashes && s[i] == '\\':
+			i++
+		case s[i] == quote && i+1 < len(s) && s[i+1] == quote:
+			i++
+		case s[i] == quote:
+			return Token{Kind: kind, Text: s[:i+1], Pos: pos}
+		}
+	}
+	return Token{Kind: kind, Text: s, Pos: pos, Unterminated: true}
+}
+
+// SplitPlaceholders splits sql at each ? marker which isn't inside a string, quoted identifier or
+// comment. The result always has one more element than there are markers.
+func SplitPlaceholders(sql string, d Dialect) []string {
+	var frags []string
+	start := 0
+	for _, t := range Tokens(sql, d) {
+		if t.Kind == Placeholder {
+			frags = append(frags, sql[start:t.Pos])
+			start = t.Pos + 1
+		}
+	}
+	return append(frags, sql[start:])
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isWordByte reports whether c can be part of an unquoted identifier. Bytes of multibyte characters
+// are, as both dialects allow them in identifiers.
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c >= 0x80
+}
+
+// Of returns the dialect described by v's ScanDialect method, like the formatters', or Any if it
+// has none.
+func Of(v any) Dialect {
+	if sd, ok := v.(interface{ ScanDialect() Dialect }); ok {
+		return sd.ScanDialect()
+	}
+	return Any
+}
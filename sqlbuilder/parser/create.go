@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+func (p *parser) parseCreate() ast.Node {
+	p.expect(`CREATE`)
+
+	orReplace := p.accept(`OR`, `REPLACE`)
+	if orReplace || p.peek().is(`VIEW`) {
+		cv := p.parseCreateView()
+		cv.OrReplace = orReplace
+		return cv
+	}
+	return p.parseCreateTable()
+}
+
+func (p *parser) parseCreateView() *ast.CreateView {
+	p.expect(`VIEW`)
+	ifNotExists := p.accept(`IF`, `NOT`, `EXISTS`)
+	name := p.parseQualifiedName()
+
+	var cols []*ast.Identifier
+	if p.accept(`(`) {
+		for _, col := range p.parseNameList() {
+			cols = append(cols, ast.NewIdentifier(col))
+		}
+		p.expect(`)`)
+	}
+
+	p.expect(`AS`)
+	cv := ast.NewCreateView(name, p.parseSelect())
+	cv.IfNotExists = ifNotExists
+	cv.Columns = cols
+	return cv
+}
+
+func (p *parser) parseCreateTable() *ast.CreateTable {
+	p.expect(`TABLE`)
+	ifNotExists := p.accept(`IF`, `NOT`, `EXISTS`)
+
+	ct := ast.NewCreateTable(p.parseQualifiedName())
+	if ifNotExists {
+		ct.CreateIfNotExists()
+	}
+
+	if p.accept(`LIKE`) {
+		ct.Like = ast.NewTableName(p.parseQualifiedName())
+		return ct
+	}
+
+	if p.accept(`(`) {
+		var pk []string
+		for {
+			if p.accept(`PRIMARY`, `KEY`) {
+				p.expect(`(`)
+				pk = p.parseNameList()
+				p.expect(`)`)
+			} else {
+				ct.AddColumn(p.parseColumnSpec())
+			}
+			if !p.accept(`,`) {
+				break
+			}
+		}
+		p.expect(`)`)
+
+		for _, name := range pk {
+			col := findColumn(ct, name)
+			if col == nil {
+				failf(p.peek().pos, `primary key column %s is not defined`, name)
+			}
+			col.SetPrimaryKey(true)
+			if ct.PrimaryKey == nil {
+				ct.PrimaryKey = ast.NewPrimaryKey()
+			}
+			ct.PrimaryKey.AddColumn(name)
+		}
+
+		p.parseTableOptions(ct)
+	}
+
+	if p.accept(`AS`) {
+		ct.AsSelect = p.parseSelect()
+	}
+	return ct
+}
+
+func findColumn(ct *ast.CreateTable, name string) *ast.ColumnSpec {
+	for _, col := range ct.Columns {
+		if col.Name.Name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseTableOptions(ct *ast.CreateTable) {
+	for {
+		switch {
+		case p.accept(`ENGINE`):
+			p.accept(`=`)
+			ct.AddOption(ast.NewTableOption(ast.TableOptionEngine, p.parseName()))
+		case p.accept(`DEFAULT`, `CHARSET`), p.accept(`CHARSET`), p.accept(`DEFAULT`, `CHARACTER`, `SET`), p.accept(`CHARACTER`, `SET`):
+			p.accept(`=`)
+			ct.AddOption(ast.NewTableOption(ast.TableOptionCharset, p.parseName()))
+		case p.accept(`COLLATE`), p.accept(`DEFAULT`, `COLLATE`):
+			p.accept(`=`)
+			ct.AddOption(ast.NewTableOption(ast.TableOptionCollate, p.parseName()))
+		case p.accept(`COMMENT`):
+			p.accept(`=`)
+			ct.AddOption(ast.NewTableOption(ast.TableOptionComment, p.parseStringValue()))
+		case p.accept(`WITHOUT`, `ROWID`):
+			ct.AddOption(ast.NewTableOption(ast.TableOptionWithoutRowID, ``))
+		case p.accept(`STRICT`):
+			ct.AddOption(ast.NewTableOption(ast.TableOptionStrict, ``))
+		case len(ct.Options) > 0 && p.accept(`,`):
+			// SQLite separates its options with commas.
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) parseColumnSpec() *ast.ColumnSpec {
+	cs := ast.NewColumnSpec(p.parseName(), p.parseColumnType())
+	for {
+		switch {
+		case p.accept(`COLLATE`):
+			cs.WithCollation(p.parseName())
+		case p.accept(`GENERATED`, `ALWAYS`, `AS`), p.accept(`AS`):
+			p.expect(`(`)
+			e := p.parseExpr()
+			p.expect(`)`)
+			stored := p.accept(`STORED`)
+			if !stored {
+				p.accept(`VIRTUAL`)
+			}
+			cs.WithGenerated(e, stored)
+		case p.accept(`NOT`, `NULL`):
+			cs.Nullability = ast.NotNull
+		case p.accept(`NULL`):
+			cs.Nullability = ast.Null
+		case p.accept(`DEFAULT`):
+			cs.WithDefault(p.parseColumnDefault())
+		case p.accept(`ON`, `UPDATE`):
+			cs.WithOnUpdate(p.parsePrimary())
+		case p.accept(`AUTO_INCREMENT`), p.accept(`AUTOINCREMENT`):
+			cs.SetAutoIncrement(true)
+		case p.accept(`COMMENT`):
+			cs.WithComment(p.parseStringValue())
+		case p.accept(`PRIMARY`, `KEY`):
+			cs.SetPrimaryKey(true)
+		default:
+			return cs
+		}
+	}
+}
+
+func (p *parser) parseColumnDefault() ast.IntoExpr {
+	if p.accept(`(`) {
+		e := p.parseExpr()
+		p.expect(`)`)
+		return e
+	}
+	return p.parseUnary()
+}
+
+func (p *parser) parseColumnType() ast.ColumnType {
+	t := p.next()
+	if t.kind != tokenWord {
+		failf(t.pos, `expected a column type, got %q`, t.text)
+	}
+
+	size := 0
+	if p.accept(`(`) {
+		size = p.parseInt()
+		p.expect(`)`)
+	}
+
+	switch upper(t.text) {
+	case `TINYINT`:
+		return ast.TinyInt()
+	case `SMALLINT`:
+		return ast.SmallInt()
+	case `INT`, `INTEGER`:
+		return ast.Int()
+	case `BIGINT`:
+		return ast.BigInt()
+	case `CHAR`:
+		return ast.Char(size)
+	case `VARCHAR`:
+		return ast.VarChar(size)
+	case `TEXT`:
+		return ast.Text(size)
+	case `TINYBLOB`:
+		return ast.TinyBlob()
+	case `BLOB`:
+		return ast.Blob()
+	case `MEDIUMBLOB`:
+		return ast.MediumBlob()
+	case `LONGBLOB`:
+		return ast.LongBlob()
+	case `DATETIME`, `NUMERIC`:
+		return ast.DateTime()
+	case `TIMESTAMP`:
+		return ast.Timestamp()
+	}
+	failf(t.pos, `unsupported column type %s`, t.text)
+	return nil
+}
+
+func (p *parser) parseInt() int {
+	t := p.next()
+	n, err := strconv.Atoi(t.text)
+	if t.kind != tokenNumber || err != nil {
+		failf(t.pos, `expected an integer, got %q`, t.text)
+	}
+	return n
+}
+
+func (p *parser) parseStringValue() string {
+	t := p.next()
+	if t.kind != tokenString {
+		failf(t.pos, `expected a string, got %q`, t.text)
+	}
+	return t.value
+}
@@ -0,0 +1,395 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+// reserved are the keywords which can't be used as unquoted identifiers. It only needs to contain
+// the keywords which could otherwise be mistaken for a column or table name.
+var reserved = map[string]bool{
+	`SELECT`: true, `FROM`: true, `WHERE`: true, `GROUP`: true, `HAVING`: true, `WINDOW`: true,
+	`ORDER`: true, `BY`: true, `LIMIT`: true, `OFFSET`: true, `FOR`: true, `AS`: true, `ON`: true,
+	`JOIN`: true, `INNER`: true, `LEFT`: true, `AND`: true, `OR`: true, `NOT`: true, `IN`: true,
+	`IS`: true, `LIKE`: true, `ESCAPE`: true, `BETWEEN`: true, `CASE`: true, `WHEN`: true, `THEN`: true,
+	`ELSE`: true, `END`: true, `SET`: true, `VALUES`: true, `DISTINCT`: true, `OVER`: true, `ASC`: true,
+	`DESC`: true, `NULL`: true, `UNION`: true,
+}
+
+func upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+func (p *parser) parseExprList() []ast.IntoExpr {
+	var es []ast.IntoExpr
+	for {
+		es = append(es, p.parseExpr())
+		if !p.accept(`,`) {
+			return es
+		}
+	}
+}
+
+func (p *parser) parseExpr() ast.IntoExpr {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() ast.IntoExpr {
+	left := p.parseAnd()
+	for p.accept(`OR`) {
+		left = ast.NewBinaryExpr(left, ast.BinaryOr, p.parseAnd())
+	}
+	return left
+}
+
+func (p *parser) parseAnd() ast.IntoExpr {
+	left := p.parseNot()
+	for p.accept(`AND`) {
+		left = ast.NewBinaryExpr(left, ast.BinaryAnd, p.parseNot())
+	}
+	return left
+}
+
+func (p *parser) parseNot() ast.IntoExpr {
+	if p.accept(`NOT`) {
+		return ast.NewUnaryExpr(p.parseNot().IntoExpr(), ast.UnaryNot)
+	}
+	return p.parseComparison()
+}
+
+var comparisons = map[string]ast.BinaryExprOperator{
+	`=`:  ast.BinaryEquals,
+	`!=`: ast.BinaryNotEquals,
+	`<>`: ast.BinaryNotEquals,
+	`>`:  ast.BinaryGreater,
+	`>=`: ast.BinaryGreaterOrEqual,
+	`<`:  ast.BinaryLess,
+	`<=`: ast.BinaryLessOrEqual,
+}
+
+func (p *parser) parseComparison() ast.IntoExpr {
+	left := p.parseOperand()
+	for {
+		t := p.peek()
+		if op, ok := comparisons[t.text]; ok && t.kind == tokenPunct {
+			p.next()
+			left = ast.NewBinaryExpr(left, op, p.parseOperand())
+			continue
+		}
+
+		switch {
+		case p.accept(`IS`, `NOT`, `NULL`):
+			left = ast.NewUnaryExpr(left.IntoExpr(), ast.UnaryIsNotNull)
+		case p.accept(`IS`, `NULL`):
+			left = ast.NewUnaryExpr(left.IntoExpr(), ast.UnaryIsNull)
+		case p.accept(`IN`):
+			left = ast.NewBinaryExpr(left, ast.BinaryIn, p.parseInList())
+		case p.accept(`NOT`, `IN`):
+			left = ast.NewBinaryExpr(left, ast.BinaryNotIn, p.parseInList())
+		case p.accept(`LIKE`):
+			left = p.parseLike(left, false)
+		case p.accept(`NOT`, `LIKE`):
+			left = p.parseLike(left, true)
+		case p.accept(`BETWEEN`):
+			left = p.parseBetween(left, ast.TernaryBetween)
+		case p.accept(`NOT`, `BETWEEN`):
+			left = p.parseBetween(left, ast.TernaryNotBetween)
+		default:
+			return left
+		}
+	}
+}
+
+// parseInList parses the right hand side of IN, which is always a tuple or a subquery, even if it has
+// a single element.
+func (p *parser) parseInList() ast.IntoExpr {
+	p.expect(`(`)
+	var e ast.IntoExpr
+	if p.peek().is(`SELECT`) {
+		e = ast.NewSubquery(p.parseSelect())
+	} else {
+		e = ast.NewTupleLiteral(p.parseExprList()...)
+	}
+	p.expect(`)`)
+	return e
+}
+
+func (p *parser) parseLike(left ast.IntoExpr, not bool) ast.IntoExpr {
+	pattern := p.parseOperand()
+	if p.accept(`ESCAPE`) {
+		op := ast.TernaryLikeEscape
+		if not {
+			op = ast.TernaryNotLikeEscape
+		}
+		return ast.NewTernaryExpr(left, op, pattern, p.parseOperand())
+	}
+
+	// MySQL patterns escape wildcards with a backslash unless told otherwise, whereas SQLite's have no
+	// escape character, so a pattern which relies on it has to say so to mean the same in both.
+	if lit, ok := pattern.(*ast.StringLiteral); ok && p.d == dialectMysql && strings.Contains(lit.Value, `\`) {
+		op := ast.TernaryLikeEscape
+		if not {
+			op = ast.TernaryNotLikeEscape
+		}
+		return ast.NewTernaryExpr(left, op, pattern, ast.NewStringLiteral(`\`))
+	}
+
+	op := ast.BinaryLike
+	if not {
+		op = ast.BinaryNotLike
+	}
+	return ast.NewBinaryExpr(left, op, pattern)
+}
+
+func (p *parser) parseBetween(left ast.IntoExpr, op ast.TernaryExprOperator) ast.IntoExpr {
+	low := p.parseOperand()
+	p.expect(`AND`)
+	return ast.NewTernaryExpr(left, op, low, p.parseOperand())
+}
+
+// parseOperand parses an operand of a comparison. The only operator which binds more tightly than
+// comparisons is SQLite's ||, which is string concatenation.
+func (p *parser) parseOperand() ast.IntoExpr {
+	first := p.parseUnary()
+	if p.d != dialectSqlite || !p.peek().is(`||`) {
+		return first
+	}
+
+	args := []ast.IntoExpr{first}
+	for p.accept(`||`) {
+		args = append(args, p.parseUnary())
+	}
+	return ast.NewBuiltin(ast.BuiltinConcat, args...)
+}
+
+func (p *parser) parseUnary() ast.IntoExpr {
+	if p.peek().is(`-`) && p.peekN(1).kind == tokenNumber {
+		p.next()
+		return ast.NewIntegerLiteral(-p.parseInt())
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() ast.IntoExpr {
+	t := p.peek()
+	switch t.kind {
+	case tokenPlaceholder:
+		p.next()
+		ph := ast.NewPlaceholderLiteral(nil)
+		p.placeholders = append(p.placeholders, ph)
+		return ph
+	case tokenNumber:
+		if _, err := strconv.Atoi(t.text); err != nil {
+			failf(t.pos, `unsupported number %s; only integers are supported`, t.text)
+		}
+		return ast.NewIntegerLiteral(p.parseInt())
+	case tokenString:
+		p.next()
+		return ast.NewStringLiteral(t.value)
+	case tokenPunct:
+		switch t.text {
+		case `*`:
+			p.next()
+			return ast.NewStarLiteral()
+		case `(`:
+			return p.parseParenthesized()
+		}
+		failf(t.pos, `unsupported operator %s`, t.text)
+	}
+
+	switch {
+	case p.accept(`NULL`):
+		return ast.NewNullLiteral()
+	case p.accept(`TRUE`):
+		return ast.NewBoolLiteral(true)
+	case p.accept(`FALSE`):
+		return ast.NewBoolLiteral(false)
+	case p.accept(`CURRENT_TIMESTAMP`):
+		return ast.NewCurrentTimestampLiteral()
+	case t.is(`CASE`):
+		return p.parseCase()
+	}
+
+	// Function names may be keywords, like MySQL's VALUES(col).
+	if t.kind == tokenWord && p.peekN(1).is(`(`) {
+		p.next()
+		return p.parseOver(p.parseCall(t.text))
+	}
+
+	name := p.parseQualifiedName()
+	if p.peek().is(`(`) {
+		return p.parseOver(p.parseCall(name))
+	}
+	return p.identifier(name)
+}
+
+// parseParenthesized parses a subquery, a tuple or a parenthesized expression. The parentheses of the
+// latter aren't kept; formatters add them wherever precedence requires.
+func (p *parser) parseParenthesized() ast.IntoExpr {
+	p.expect(`(`)
+	var e ast.IntoExpr
+	if p.peek().is(`SELECT`) {
+		e = ast.NewSubquery(p.parseSelect())
+	} else if es := p.parseExprList(); len(es) == 1 {
+		e = es[0]
+	} else {
+		e = ast.NewTupleLiteral(es...)
+	}
+	p.expect(`)`)
+	return e
+}
+
+func (p *parser) parseCase() ast.IntoExpr {
+	p.expect(`CASE`)
+
+	var operand ast.IntoExpr
+	if !p.peek().is(`WHEN`) {
+		operand = p.parseExpr()
+	}
+	c := ast.NewCase(operand)
+
+	for p.accept(`WHEN`) {
+		cond := p.parseExpr()
+		p.expect(`THEN`)
+		c.AddWhen(cond, p.parseExpr())
+	}
+	if len(c.Whens) == 0 {
+		p.unexpected(`WHEN`)
+	}
+	if p.accept(`ELSE`) {
+		c.WithElse(p.parseExpr())
+	}
+	p.expect(`END`)
+	return c
+}
+
+// parseCall parses the arguments of a function call and translates functions which have a Builtin or
+// other special node.
+func (p *parser) parseCall(name string) ast.IntoExpr {
+	pos := p.peek().pos
+	p.expect(`(`)
+
+	switch upper(name) {
+	case `GROUP_CONCAT`:
+		return p.parseGroupConcat()
+	case `EXTRACT`:
+		unit := p.parseTimeUnit()
+		p.expect(`FROM`)
+		e := p.parseExpr()
+		p.expect(`)`)
+		return ast.NewBuiltin(ast.BuiltinExtract, e).WithUnit(unit)
+	case `CAST`:
+		e := p.parseExpr()
+		p.expect(`AS`)
+		typ := p.parseName()
+		p.expect(`)`)
+		return p.translateCast(pos, e, typ)
+	}
+
+	var args []ast.IntoExpr
+	if p.accept(`DISTINCT`) {
+		args = []ast.IntoExpr{ast.NewDistinct(p.parseExprList()...)}
+	} else if !p.peek().is(`)`) {
+		args = p.parseExprList()
+	}
+	p.expect(`)`)
+
+	return p.translateFunction(name, args)
+}
+
+func (p *parser) parseGroupConcat() ast.IntoExpr {
+	distinct := p.accept(`DISTINCT`)
+	gc := ast.NewGroupConcat(p.parseExpr())
+	gc.Distinct = distinct
+
+	// SQLite passes the separator as a second argument; MySQL has a SEPARATOR clause at the end.
+	if p.accept(`,`) {
+		gc.Separator = p.parseStringValue()
+	}
+	if p.peek().is(`ORDER`) {
+		gc.OrderBy = p.parseOrderBy()
+	}
+	if p.accept(`SEPARATOR`) {
+		gc.Separator = p.parseStringValue()
+	}
+	p.expect(`)`)
+	return gc
+}
+
+func (p *parser) parseTimeUnit() ast.TimeUnit {
+	t := p.next()
+	for u := ast.TimeUnitYear; u <= ast.TimeUnitSecond; u++ {
+		if t.is(u.String()) {
+			return u
+		}
+	}
+	failf(t.pos, `unsupported time unit %q`, t.text)
+	return 0
+}
+
+// parseOver parses the OVER clause of a window function, if there is one.
+func (p *parser) parseOver(fn ast.IntoExpr) ast.IntoExpr {
+	if !p.accept(`OVER`) {
+		return fn
+	}
+	if !p.accept(`(`) {
+		return ast.NewNamedWindowFunc(fn, p.parseName())
+	}
+	spec := p.parseWindowSpec()
+	p.expect(`)`)
+	return ast.NewWindowFunc(fn, spec)
+}
+
+func (p *parser) parseWindowSpec() *ast.WindowSpec {
+	spec := &ast.WindowSpec{}
+	if p.accept(`PARTITION`, `BY`) {
+		spec.PartitionBy = ast.IntoExprs(p.parseExprList()...)
+	}
+	if p.peek().is(`ORDER`) {
+		spec.OrderBy = p.parseOrderBy()
+	}
+
+	var unit ast.FrameUnit
+	switch {
+	case p.accept(`ROWS`):
+		unit = ast.FrameRows
+	case p.accept(`RANGE`):
+		unit = ast.FrameRange
+	default:
+		return spec
+	}
+
+	spec.Frame = &ast.WindowFrame{Unit: unit}
+	if p.accept(`BETWEEN`) {
+		spec.Frame.Start = p.parseFrameBound()
+		p.expect(`AND`)
+		spec.Frame.End = p.parseFrameBound()
+	} else {
+		spec.Frame.Start = p.parseFrameBound()
+		spec.Frame.End = ast.FrameBound{Kind: ast.BoundCurrentRow}
+	}
+	return spec
+}
+
+func (p *parser) parseFrameBound() ast.FrameBound {
+	switch {
+	case p.accept(`UNBOUNDED`, `PRECEDING`):
+		return ast.FrameBound{Kind: ast.BoundUnboundedPreceding}
+	case p.accept(`UNBOUNDED`, `FOLLOWING`):
+		return ast.FrameBound{Kind: ast.BoundUnboundedFollowing}
+	case p.accept(`CURRENT`, `ROW`):
+		return ast.FrameBound{Kind: ast.BoundCurrentRow}
+	}
+
+	n := p.parseInt()
+	switch {
+	case p.accept(`PRECEDING`):
+		return ast.FrameBound{Kind: ast.BoundPreceding, Offset: n}
+	case p.accept(`FOLLOWING`):
+		return ast.FrameBound{Kind: ast.BoundFollowing, Offset: n}
+	}
+	p.unexpected(`PRECEDING or FOLLOWING`)
+	return ast.FrameBound{}
+}
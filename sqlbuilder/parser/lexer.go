@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/scan"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenWord is a keyword or an unquoted identifier.
+	tokenWord
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenPlaceholder
+	// tokenPunct is an operator or punctuation, e.g. ( or <=.
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	// text is the token as written in the source.
+	text string
+	// value is the unescaped contents of a string token.
+	value string
+	pos   int
+}
+
+// is reports whether t is the given keyword or punctuation. Keywords are case insensitive.
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokenWord:
+		return strings.EqualFold(t.text, s)
+	case tokenPunct:
+		return t.text == s
+	}
+	return false
+}
+
+var punctuation = []string{`!=`, `<>`, `<=`, `>=`, `||`, `(`, `)`, `,`, `;`, `.`, `*`, `=`, `<`, `>`, `+`, `-`, `/`, `%`}
+
+func lex(sql string, d dialect) []token {
+	sd := scan.Sqlite
+	if d == dialectMysql {
+		sd = scan.Mysql
+	}
+
+	var toks []token
+	for _, t := range scan.Tokens(sql, sd) {
+		tok := token{text: t.Text, pos: t.Pos}
+		switch t.Kind {
+		case scan.Space, scan.LineComment:
+			continue
+		case scan.BlockComment:
+			if t.Unterminated {
+				failf(t.Pos, `unterminated comment`)
+			}
+			continue
+		case scan.String:
+			if t.Unterminated {
+				failf(t.Pos, `unterminated string`)
+			}
+			tok.kind = tokenString
+			tok.value = unquoteString(t.Text, d)
+		case scan.QuotedIdent:
+			if t.Unterminated {
+				failf(t.Pos, `unterminated quoted identifier`)
+			}
+			tok.kind = tokenQuotedIdent
+		case scan.Placeholder:
+			tok.kind = tokenPlaceholder
+		case scan.Number:
+			tok.kind = tokenNumber
+		case scan.Word:
+			tok.kind = tokenWord
+		default:
+			if !slices.Contains(punctuation, t.Text) {
+				failf(t.Pos, `unexpected character %q`, t.Text[0])
+			}
+			tok.kind = tokenPunct
+		}
+		toks = append(toks, tok)
+	}
+	return append(toks, token{kind: tokenEOF, pos: len(sql)})
+}
+
+// unquoteString returns the value of the string s, which the scanner has found to be terminated. Quotes
+// are escaped by doubling them; MySQL also has backslash escapes.
+func unquoteString(s string, d dialect) string {
+	quote := s[0]
+	s = s[1 : len(s)-1]
+	sb := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == quote:
+			// The scanner only lets a quote through if it's doubled.
+			sb.WriteByte(quote)
+			i++
+		case c == '\\' && d == dialectMysql && i+1 < len(s):
+			i++
+			sb.WriteString(unescapeMysql(s[i]))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func unescapeMysql(c byte) string {
+	switch c {
+	case '0':
+		return "\x00"
+	case 'b':
+		return "\b"
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case 'Z':
+		return "\x1a"
+	}
+	// Everything else (including \\, \' and \") stands for itself. MySQL keeps the backslash in \% and
+	// \_ so that they still escape LIKE wildcards.
+	if c == '%' || c == '_' {
+		return `\` + string(c)
+	}
+	return string(c)
+}
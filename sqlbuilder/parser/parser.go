@@ -0,0 +1,414 @@
+// Package parser parses SQL into the AST the formatters render. It understands the subset of SQL the
+// AST can represent: SELECT, INSERT, UPDATE, DELETE, CREATE TABLE, CREATE VIEW and DROP VIEW, with the
+// expressions the builders produce. Anything else is an error rather than being passed through.
+//
+// Dialect specific spellings are translated back into the AST's portable nodes, so a statement parsed
+// with Mysql can be formatted with formatter.Sqlite and vice versa.
+package parser
+
+import (
+	"fmt"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+type dialect int
+
+const (
+	dialectMysql dialect = iota
+	dialectSqlite
+)
+
+// Mysql parses MySQL statements, such as those produced by formatter.Mysql.
+type Mysql struct{}
+
+// Parse parses a single statement. Each ? placeholder is bound to the corresponding arg; if no args
+// are given, placeholders are bound to nil.
+func (Mysql) Parse(sql string, args ...any) (ast.Node, error) {
+	return parse(sql, dialectMysql, args)
+}
+
+// Sqlite parses SQLite statements, such as those produced by formatter.Sqlite.
+type Sqlite struct{}
+
+// Parse parses a single statement. Each ? placeholder is bound to the corresponding arg; if no args
+// are given, placeholders are bound to nil.
+func (Sqlite) Parse(sql string, args ...any) (ast.Node, error) {
+	return parse(sql, dialectSqlite, args)
+}
+
+// parseError unwinds the parser when it hits something it doesn't understand; parse recovers it.
+type parseError struct {
+	err error
+}
+
+func failf(pos int, format string, args ...any) {
+	panic(parseError{err: fmt.Errorf(`at offset %d: %s`, pos, fmt.Sprintf(format, args...))})
+}
+
+func parse(sql string, d dialect, args []any) (n ast.Node, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if pe, ok := r.(parseError); ok {
+			err = pe.err
+			return
+		}
+		panic(r)
+	}()
+
+	p := &parser{
+		d:    d,
+		toks: lex(sql, d),
+	}
+	n = p.parseStatement()
+
+	if len(args) > 0 && len(args) != len(p.placeholders) {
+		return nil, fmt.Errorf(`statement has %d placeholders but %d arguments`, len(p.placeholders), len(args))
+	}
+	for i, a := range args {
+		p.placeholders[i].For = a
+	}
+	return n, nil
+}
+
+type parser struct {
+	d    dialect
+	toks []token
+	pos  int
+
+	placeholders []*ast.PlaceholderLiteral
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) peekN(n int) token {
+	if p.pos+n >= len(p.toks) {
+		return p.toks[len(p.toks)-1]
+	}
+	return p.toks[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the given sequence of keywords or punctuation if it comes next.
+func (p *parser) accept(ss ...string) bool {
+	for i, s := range ss {
+		if !p.peekN(i).is(s) {
+			return false
+		}
+	}
+	p.pos += len(ss)
+	return true
+}
+
+func (p *parser) expect(ss ...string) {
+	for _, s := range ss {
+		if !p.accept(s) {
+			p.unexpected(s)
+		}
+	}
+}
+
+func (p *parser) unexpected(want string) {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		failf(t.pos, `expected %s, got end of input`, want)
+	}
+	failf(t.pos, `expected %s, got %q`, want, t.text)
+}
+
+func (p *parser) parseStatement() ast.Node {
+	var n ast.Node
+	switch t := p.peek(); {
+	case t.is(`SELECT`):
+		n = p.parseSelect()
+	case t.is(`INSERT`):
+		n = p.parseInsert()
+	case t.is(`UPDATE`):
+		n = p.parseUpdate()
+	case t.is(`DELETE`):
+		n = p.parseDelete()
+	case t.is(`CREATE`):
+		n = p.parseCreate()
+	case t.is(`DROP`):
+		n = p.parseDropView()
+	default:
+		p.unexpected(`a statement`)
+	}
+
+	p.accept(`;`)
+	if p.peek().kind != tokenEOF {
+		p.unexpected(`end of statement`)
+	}
+	return n
+}
+
+func (p *parser) parseSelect() *ast.Select {
+	p.expect(`SELECT`)
+
+	var exprs []ast.IntoExpr
+	distinct := p.accept(`DISTINCT`)
+	for {
+		e := p.parseExpr()
+		if p.accept(`AS`) {
+			e = ast.NewAlias(e, p.parseName())
+		}
+		exprs = append(exprs, e)
+		if !p.accept(`,`) {
+			break
+		}
+	}
+	if distinct {
+		exprs = []ast.IntoExpr{ast.NewDistinct(exprs...)}
+	}
+
+	p.expect(`FROM`)
+	s := ast.NewSelect(p.parseTableExpr(), exprs...)
+
+	if p.accept(`WHERE`) {
+		s.WithWhere(p.parseExpr())
+	}
+	if p.accept(`GROUP`, `BY`) {
+		s.WithGroupBy(p.parseExprList()...)
+	}
+	if p.accept(`HAVING`) {
+		s.WithHaving(p.parseExpr())
+	}
+	if p.accept(`WINDOW`) {
+		for {
+			name := p.parseName()
+			p.expect(`AS`, `(`)
+			s.WithWindow(name, p.parseWindowSpec())
+			p.expect(`)`)
+			if !p.accept(`,`) {
+				break
+			}
+		}
+	}
+	if p.peek().is(`ORDER`) {
+		s.WithOrders(p.parseOrderBy().Orders...)
+	}
+	if p.peek().is(`LIMIT`) {
+		s.Limit = p.parseLimit()
+	}
+
+	switch {
+	case p.accept(`FOR`, `UPDATE`):
+		s.WithLock(ast.ForUpdateLock)
+	case p.accept(`FOR`, `SHARE`), p.accept(`LOCK`, `IN`, `SHARE`, `MODE`):
+		s.WithLock(ast.SharedLock)
+	}
+
+	return s
+}
+
+func (p *parser) parseTableExpr() ast.TableExpr {
+	left := p.parseTableFactor()
+	for {
+		var kind ast.JoinKind
+		switch {
+		case p.accept(`INNER`, `JOIN`), p.accept(`JOIN`):
+			kind = ast.JoinKindInner
+		case p.accept(`LEFT`, `OUTER`, `JOIN`), p.accept(`LEFT`, `JOIN`):
+			kind = ast.JoinKindLeft
+		default:
+			return left
+		}
+
+		right := p.parseTableFactor()
+		p.expect(`ON`)
+		left = ast.NewJoin(kind, left, right, p.parseExpr().IntoExpr())
+	}
+}
+
+func (p *parser) parseTableFactor() ast.TableExpr {
+	if p.peek().is(`(`) {
+		failf(p.peek().pos, `subqueries and parenthesized joins in FROM are not supported`)
+	}
+
+	var te ast.TableExpr = ast.NewTableName(p.parseQualifiedName())
+	if p.accept(`AS`) {
+		te = &ast.TableAlias{
+			ForExpr: te,
+			As:      ast.NewIdentifier(p.parseName()),
+		}
+	}
+	return te
+}
+
+func (p *parser) parseOrderBy() *ast.OrderBy {
+	p.expect(`ORDER`, `BY`)
+
+	ob := &ast.OrderBy{}
+	for {
+		o := ast.NewOrder(p.parseExpr(), ast.OrderAsc)
+		if p.accept(`DESC`) {
+			o.Direction = ast.OrderDesc
+		} else {
+			p.accept(`ASC`)
+		}
+		ob.Orders = append(ob.Orders, o)
+		if !p.accept(`,`) {
+			return ob
+		}
+	}
+}
+
+func (p *parser) parseLimit() *ast.Limit {
+	p.expect(`LIMIT`)
+
+	first := p.parseExpr()
+	switch {
+	case p.accept(`,`):
+		return ast.NewLimit(first, p.parseExpr())
+	case p.accept(`OFFSET`):
+		return ast.NewLimit(p.parseExpr(), first)
+	}
+	return ast.NewLimit(ast.None(), first)
+}
+
+func (p *parser) parseInsert() *ast.Insert {
+	p.expect(`INSERT`, `INTO`)
+	into := p.parseTableFactor()
+
+	p.expect(`(`)
+	var cols []*ast.Identifier
+	for _, name := range p.parseNameList() {
+		cols = append(cols, ast.NewIdentifier(name))
+	}
+	p.expect(`)`)
+
+	ins := ast.NewInsert(into, cols...)
+
+	p.expect(`VALUES`)
+	for {
+		p.expect(`(`)
+		ins.AddValues(p.parseExprList()...)
+		p.expect(`)`)
+		if !p.accept(`,`) {
+			break
+		}
+	}
+
+	switch {
+	case p.accept(`ON`, `DUPLICATE`, `KEY`, `UPDATE`):
+		ins.OnDuplicateKey = ast.NewOnDuplicateKey()
+	case p.accept(`ON`, `CONFLICT`):
+		p.expect(`(`)
+		var keys []*ast.Identifier
+		for _, name := range p.parseNameList() {
+			keys = append(keys, ast.NewIdentifier(name))
+		}
+		p.expect(`)`, `DO`, `UPDATE`, `SET`)
+		ins.OnDuplicateKey = ast.NewOnDuplicateKey(keys...)
+	default:
+		return ins
+	}
+
+	for _, a := range p.parseAssignments() {
+		ins.OnDuplicateKey.Updates = append(ins.OnDuplicateKey.Updates, a)
+	}
+	return ins
+}
+
+func (p *parser) parseAssignments() []*ast.BinaryExpr {
+	var res []*ast.BinaryExpr
+	for {
+		col := ast.NewIdentifier(p.parseQualifiedName())
+		p.expect(`=`)
+		res = append(res, ast.NewBinaryExpr(col, ast.BinaryEquals, p.parseExpr()))
+		if !p.accept(`,`) {
+			return res
+		}
+	}
+}
+
+func (p *parser) parseUpdate() *ast.Update {
+	p.expect(`UPDATE`)
+	u := ast.NewUpdate(p.parseTableExpr())
+
+	p.expect(`SET`)
+	for _, a := range p.parseAssignments() {
+		u.AddAssignments(a)
+	}
+
+	if p.accept(`WHERE`) {
+		u.WithWhere(p.parseExpr())
+	}
+	if p.peek().is(`ORDER`) {
+		u.WithOrders(p.parseOrderBy().Orders...)
+	}
+	if p.peek().is(`LIMIT`) {
+		u.Limit = p.parseLimit()
+	}
+	return u
+}
+
+func (p *parser) parseDelete() *ast.Delete {
+	p.expect(`DELETE`, `FROM`)
+	d := ast.NewDelete(p.parseTableExpr())
+
+	if p.accept(`WHERE`) {
+		d.WithWhere(p.parseExpr())
+	}
+	if p.peek().is(`ORDER`) {
+		d.WithOrders(p.parseOrderBy().Orders...)
+	}
+	if p.peek().is(`LIMIT`) {
+		d.Limit = p.parseLimit()
+	}
+	return d
+}
+
+func (p *parser) parseDropView() *ast.DropView {
+	p.expect(`DROP`, `VIEW`)
+	ifExists := p.accept(`IF`, `EXISTS`)
+
+	dv := ast.NewDropView(p.parseQualifiedName())
+	dv.IfExists = ifExists
+	return dv
+}
+
+// parseName parses an identifier, keeping any quotes.
+func (p *parser) parseName() string {
+	t := p.peek()
+	if t.kind == tokenQuotedIdent || t.kind == tokenWord && !reserved[upper(t.text)] {
+		return p.next().text
+	}
+	p.unexpected(`an identifier`)
+	return ``
+}
+
+// parseQualifiedName parses a possibly qualified identifier, like db.table or table.column.
+func (p *parser) parseQualifiedName() string {
+	name := p.parseName()
+	for p.peek().is(`.`) {
+		p.next()
+		if p.peek().is(`*`) {
+			return name + `.` + p.next().text
+		}
+		name += `.` + p.parseName()
+	}
+	return name
+}
+
+func (p *parser) parseNameList() []string {
+	var names []string
+	for {
+		names = append(names, p.parseQualifiedName())
+		if !p.accept(`,`) {
+			return names
+		}
+	}
+}
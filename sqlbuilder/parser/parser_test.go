@@ -0,0 +1,320 @@
+package parser
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cszczepaniak/gotest/assert"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/formatter"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+type dialectCase struct {
+	name string
+	f    interface {
+		FormatNode(w io.Writer, n ast.Node) error
+	}
+	parse func(sql string, args ...any) (ast.Node, error)
+}
+
+var dialects = []dialectCase{
+	{`mysql`, formatter.Mysql{Version: formatter.MySQL80}, Mysql{}.Parse},
+	{`sqlite`, formatter.Sqlite{}, Sqlite{}.Parse},
+}
+
+func format(t *testing.T, f interface {
+	FormatNode(w io.Writer, n ast.Node) error
+}, n ast.Node) string {
+	t.Helper()
+
+	sb := &strings.Builder{}
+	assert.NoError(t, f.FormatNode(sb, n))
+	return sb.String()
+}
+
+// assertRoundTrip checks that format(parse(format(n))) == format(n) in every dialect which can format n.
+func assertRoundTrip(t *testing.T, n ast.Node) {
+	t.Helper()
+
+	for _, d := range dialects {
+		sb := &strings.Builder{}
+		if err := d.f.FormatNode(sb, n); err != nil {
+			continue
+		}
+		sql := sb.String()
+
+		t.Run(d.name, func(t *testing.T) {
+			parsed, err := d.parse(sql, ast.GetArgs(n)...)
+			assert.NoError(t, err)
+			assert.Equal(t, format(t, d.f, parsed), sql)
+			assert.Equal(t, ast.GetArgs(parsed), ast.GetArgs(n))
+		})
+	}
+}
+
+func id(name string) *ast.Identifier {
+	return ast.NewIdentifier(name)
+}
+
+func ph(v any) *ast.PlaceholderLiteral {
+	return ast.NewPlaceholderLiteral(v)
+}
+
+func eq(l, r ast.IntoExpr) *ast.BinaryExpr {
+	return ast.NewBinaryExpr(l, ast.BinaryEquals, r)
+}
+
+func TestRoundTripExpressions(t *testing.T) {
+	exprs := []ast.IntoExpr{
+		eq(id("a"), ph(1)),
+		ast.NewBinaryExpr(id("a"), ast.BinaryNotEquals, ast.NewIntegerLiteral(-3)),
+		ast.NewBinaryExpr(id("a"), ast.BinaryGreater, ast.NewStringLiteral(`it's a \ "test"`)),
+		ast.NewBinaryExpr(id("a"), ast.BinaryGreaterOrEqual, ast.NewNullLiteral()),
+		ast.NewBinaryExpr(id("t.a"), ast.BinaryLess, id("u.b")),
+		ast.NewBinaryExpr(id("a"), ast.BinaryLessOrEqual, ast.NewCurrentTimestampLiteral()),
+		ast.NewBinaryExpr(id("a"), ast.BinaryIn, ast.NewTupleLiteral(ph(1))),
+		ast.NewBinaryExpr(id("a"), ast.BinaryNotIn, ast.NewTupleLiteral(ph(1), ph(2))),
+		ast.NewBinaryExpr(id("a"), ast.BinaryLike, ph(`%x%`)),
+		ast.NewBinaryExpr(id("a"), ast.BinaryNotLike, ph(`%x%`)),
+		ast.NewTernaryExpr(id("a"), ast.TernaryLikeEscape, ph(`%x%`), ast.NewStringLiteral(`!`)),
+		ast.NewTernaryExpr(id("a"), ast.TernaryNotLikeEscape, ph(`%x%`), ast.NewStringLiteral(`!`)),
+		ast.NewTernaryExpr(id("a"), ast.TernaryBetween, ph(1), ph(2)),
+		ast.NewTernaryExpr(id("a"), ast.TernaryNotBetween, ph(1), ph(2)),
+		ast.NewUnaryExpr(id("a"), ast.UnaryIsNull),
+		ast.NewUnaryExpr(id("a"), ast.UnaryIsNotNull),
+		ast.NewUnaryExpr(eq(id("a"), ph(1)), ast.UnaryNot),
+		ast.NewBoolLiteral(true),
+		ast.NewTimeLiteral(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		ast.NewBinaryExpr(
+			ast.NewBinaryExpr(eq(id("a"), ph(1)), ast.BinaryOr, eq(id("b"), ph(2))),
+			ast.BinaryAnd,
+			ast.NewUnaryExpr(ast.NewBinaryExpr(eq(id("c"), ph(3)), ast.BinaryAnd, eq(id("d"), ph(4))), ast.UnaryNot),
+		),
+		ast.NewBinaryExpr(ast.NewTupleLiteral(id("a"), id("b")), ast.BinaryEquals, ast.NewTupleLiteral(ph(1), ph(2))),
+		// SQLite expands this into ORs, which are parenthesized whether or not the context needs them,
+		// so only a context which needs them round trips.
+		ast.NewBinaryExpr(
+			ast.NewBinaryExpr(
+				ast.NewTupleLiteral(id("a"), id("b")),
+				ast.BinaryIn,
+				ast.NewTupleLiteral(ast.NewTupleLiteral(ph(1), ph(2)), ast.NewTupleLiteral(ph(3), ph(4))),
+			),
+			ast.BinaryAnd,
+			eq(id("c"), ph(5)),
+		),
+		ast.NewBinaryExpr(id("a"), ast.BinaryIn, ast.NewSubquery(
+			ast.NewSelect(ast.NewTableName("t"), id("b")).WithWhere(eq(id("c"), ph(1))),
+		)),
+		ast.NewFunction("COALESCE", id("a"), ph(1)),
+		ast.NewFunction("COUNT", ast.NewStarLiteral()),
+		ast.NewFunction("COUNT", ast.NewDistinct(id("a"))),
+		ast.NewCase(nil).AddWhen(eq(id("a"), ph(1)), ast.NewStringLiteral("one")).WithElse(ph(2)),
+		ast.NewCase(id("a")).AddWhen(ph(1), ph(2)).AddWhen(ph(3), ast.NewCase(nil).AddWhen(id("b"), ph(4))),
+		ast.NewBuiltin(ast.BuiltinLength, id("a")),
+		ast.NewBuiltin(ast.BuiltinConcat, id("a"), ph(`-`), ast.NewFunction("LOWER", id("b"))),
+		ast.NewBuiltin(ast.BuiltinNow),
+		ast.NewAlias(ast.NewRaw(`a`), "x"),
+		&ast.WindowFunc{
+			Func: ast.NewFunction("ROW_NUMBER"),
+			Window: &ast.WindowSpec{
+				PartitionBy: []ast.Expr{id("a")},
+				OrderBy:     &ast.OrderBy{Orders: []ast.Order{ast.NewOrder(id("b"), ast.OrderDesc)}},
+				Frame: &ast.WindowFrame{
+					Unit:  ast.FrameRows,
+					Start: ast.FrameBound{Kind: ast.BoundPreceding, Offset: 2},
+					End:   ast.FrameBound{Kind: ast.BoundUnboundedFollowing},
+				},
+			},
+		},
+		ast.NewNamedWindowFunc(ast.NewFunction("RANK"), "w"),
+	}
+	for u := ast.TimeUnitYear; u <= ast.TimeUnitSecond; u++ {
+		exprs = append(exprs,
+			ast.NewBuiltin(ast.BuiltinExtract, id("a")).WithUnit(u),
+			ast.NewBuiltin(ast.BuiltinTruncTime, id("a")).WithUnit(u),
+		)
+	}
+
+	gc := ast.NewGroupConcat(id("a"))
+	gc.Separator = `; `
+	gc.OrderBy = &ast.OrderBy{Orders: []ast.Order{ast.NewOrder(id("b"), ast.OrderAsc)}}
+	exprs = append(exprs, gc)
+
+	distinctGC := ast.NewGroupConcat(id("a"))
+	distinctGC.Distinct = true
+	exprs = append(exprs, distinctGC)
+
+	for _, e := range exprs {
+		sel := ast.NewSelect(ast.NewTableName("t"), e)
+		t.Run(format(t, formatter.Sqlite{}, sel), func(t *testing.T) {
+			assertRoundTrip(t, sel)
+		})
+	}
+}
+
+func TestRoundTripStatements(t *testing.T) {
+	join := ast.NewJoin(
+		ast.JoinKindLeft,
+		ast.NewJoin(
+			ast.JoinKindInner,
+			&ast.TableAlias{ForExpr: ast.NewTableName("db.t"), As: id("x")},
+			ast.NewTableName("u"),
+			eq(id("x.a"), id("u.a")),
+		),
+		ast.NewTableName("v"),
+		ast.NewBinaryExpr(eq(id("v.a"), id("u.a")), ast.BinaryAnd, eq(id("v.b"), ph(1))),
+	)
+	sel := ast.NewSelect(join, id("x.*"), ast.NewAlias(ast.NewFunction("COUNT", ast.NewStarLiteral()), "n")).
+		WithWhere(eq(id("x.b"), ph(2))).
+		WithGroupBy(id("x.a")).
+		WithHaving(ast.NewBinaryExpr(id("n"), ast.BinaryGreater, ph(3))).
+		WithWindow("w", &ast.WindowSpec{PartitionBy: []ast.Expr{id("x.a")}}).
+		WithOrders(ast.NewOrder(id("n"), ast.OrderDesc), ast.NewOrder(id("x.a"), ast.OrderAsc)).
+		WithLimit(ph(4), ph(5)).
+		WithLock(ast.ForUpdateLock)
+	assertRoundTrip(t, sel)
+	assertRoundTrip(t, ast.NewSelect(ast.NewTableName("t"), ast.NewDistinct(id("a"), id("b"))).WithLock(ast.SharedLock))
+
+	ins := ast.NewInsert(ast.NewTableName("t"), id("a"), id("b"))
+	ins.AddValues(ph(1), ph(2))
+	ins.AddValues(ph(3), ast.NewNullLiteral())
+	assertRoundTrip(t, ins)
+	ins.OnDuplicateKeyUpdate([]*ast.Identifier{id("a")}, id("b"), ast.NewValuesLiteral(id("b")))
+	ins.OnDuplicateKeyUpdate([]*ast.Identifier{id("a")}, id("c"), ph(4))
+	assertRoundTrip(t, ins)
+
+	upd := ast.NewUpdate(ast.NewTableName("t"))
+	upd.AddAssignments(eq(id("a"), ph(1)), eq(id("b"), ast.NewCase(nil).AddWhen(id("c"), ph(2)).WithElse(id("b"))))
+	upd.WithWhere(eq(id("c"), ph(3))).WithOrders(ast.NewOrder(id("a"), ast.OrderAsc)).WithLimit(ast.None(), ph(4))
+	assertRoundTrip(t, upd)
+
+	del := ast.NewDelete(ast.NewTableName("t")).
+		WithWhere(ast.NewUnaryExpr(id("a"), ast.UnaryIsNull)).
+		WithOrders(ast.NewOrder(id("a"), ast.OrderDesc)).
+		WithLimit(ast.None(), ph(1))
+	assertRoundTrip(t, del)
+
+	ct := ast.NewCreateTable("t")
+	ct.CreateIfNotExists()
+	ct.AddColumn(ast.NewColumnSpec("id", ast.BigInt()).SetAutoIncrement(true).SetPrimaryKey(true))
+	ct.AddColumn(ast.NewColumnSpec("name", ast.VarChar(255)).WithCollation("utf8mb4_bin"))
+	ct.AddColumn(ast.NewColumnSpec("n", ast.Int()).WithDefault(ast.NewIntegerLiteral(-1)))
+	ct.AddColumn(ast.NewColumnSpec("s", ast.Text(10)).WithDefault(ast.NewStringLiteral("x")))
+	ct.AddColumn(ast.NewColumnSpec("u", ast.Char(36)).WithDefault(ast.NewFunction("UUID")))
+	ct.AddColumn(ast.NewColumnSpec("b", ast.Blob()))
+	ct.AddColumn(ast.NewColumnSpec("len", ast.Int()).WithGenerated(ast.NewBuiltin(ast.BuiltinLength, id("name")), true))
+	notNull := false
+	ct.AddColumn(ast.NewColumnSpec("created", ast.DateTime()).
+		WithNullabilityFromBool(&notNull).
+		WithDefault(ast.NewCurrentTimestampLiteral()).
+		WithOnUpdate(ast.NewCurrentTimestampLiteral()).
+		WithComment("it's created"))
+	ct.AddOption(ast.NewTableOption(ast.TableOptionEngine, "InnoDB"))
+	ct.AddOption(ast.NewTableOption(ast.TableOptionCharset, "utf8mb4"))
+	ct.AddOption(ast.NewTableOption(ast.TableOptionComment, "a table"))
+	assertRoundTrip(t, ct)
+
+	strict := ast.NewCreateTable("t")
+	strict.AddColumn(ast.NewColumnSpec("a", ast.Int()).SetPrimaryKey(true))
+	strict.AddOption(ast.NewTableOption(ast.TableOptionWithoutRowID, ""))
+	strict.AddOption(ast.NewTableOption(ast.TableOptionStrict, ""))
+	assertRoundTrip(t, strict)
+
+	like := ast.NewCreateTable("t")
+	like.Like = ast.NewTableName("u")
+	assertRoundTrip(t, like)
+
+	asSelect := ast.NewCreateTable("t")
+	asSelect.AsSelect = ast.NewSelect(ast.NewTableName("u"), id("a"))
+	assertRoundTrip(t, asSelect)
+
+	cv := ast.NewCreateView("v", ast.NewSelect(ast.NewTableName("t"), id("a")))
+	cv.Columns = []*ast.Identifier{id("x")}
+	assertRoundTrip(t, cv)
+	cv.OrReplace = true
+	assertRoundTrip(t, cv)
+
+	dv := ast.NewDropView("v")
+	dv.IfExists = true
+	assertRoundTrip(t, dv)
+}
+
+func TestParseArgs(t *testing.T) {
+	n, err := Mysql{}.Parse(`SELECT a FROM t WHERE b = ? AND c IN (?,?) LIMIT ?`, 1, 2, 3, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, ast.GetArgs(n), []any{1, 2, 3, 4})
+
+	n, err = Mysql{}.Parse(`SELECT a FROM t WHERE b = ?`)
+	assert.NoError(t, err)
+	assert.Equal(t, ast.GetArgs(n), []any{nil})
+
+	_, err = Mysql{}.Parse(`SELECT a FROM t WHERE b = ?`, 1, 2)
+	assert.Error(t, err)
+}
+
+func TestTranslate(t *testing.T) {
+	n, err := Mysql{}.Parse(
+		"select `id`, char_length(name), concat(a, 'x'), group_concat(distinct b order by b separator ',') "+
+			"from t inner join u on t.id = u.t_id "+
+			"where extract(month from created) = ? and name like 'a\\_%' -- a comment\n"+
+			"order by id desc limit 10",
+		2,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		format(t, formatter.Sqlite{}, n),
+		"SELECT `id`,LENGTH(name),(a || 'x'),GROUP_CONCAT(DISTINCT b ORDER BY b ASC) "+
+			"FROM t INNER JOIN u ON t.id = u.t_id "+
+			"WHERE CAST(STRFTIME('%m',created) AS INTEGER) = ? AND name LIKE 'a\\_%' ESCAPE '\\' "+
+			"ORDER BY id DESC LIMIT 10",
+	)
+
+	// Only patterns which use MySQL's escape character need it spelled out.
+	n, err = Mysql{}.Parse(`SELECT a FROM t WHERE a LIKE 'x%' AND b NOT LIKE '100\%'`)
+	assert.NoError(t, err)
+	assert.Equal(t, format(t, formatter.Sqlite{}, n), `SELECT a FROM t WHERE a LIKE 'x%' AND b NOT LIKE '100\%' ESCAPE '\'`)
+	assert.Equal(t, format(t, formatter.Mysql{}, n), `SELECT a FROM t WHERE a LIKE 'x%' AND b NOT LIKE '100\\%' ESCAPE '\\'`)
+
+	n, err = Sqlite{}.Parse(`INSERT INTO t (a,b) VALUES (?,?) ON CONFLICT (a) DO UPDATE SET b = excluded.b`)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		format(t, formatter.Mysql{}, n),
+		`INSERT INTO t (a,b) VALUES (?,?)ON DUPLICATE KEY UPDATE b = VALUES(b)`,
+	)
+
+	// SQLite spells TruncTime and Now with STRFTIME, in the layout the formatter writes times in.
+	n, err = Sqlite{}.Parse(`SELECT STRFTIME('%Y-%m-%dT00:00:00.000Z',a) FROM t WHERE a < STRFTIME('%Y-%m-%dT%H:%M:%fZ','now')`)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		format(t, formatter.Mysql{}, n),
+		`SELECT CAST(DATE_FORMAT(a,'%Y-%m-%d 00:00:00') AS DATETIME) FROM t WHERE a < NOW()`,
+	)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, sql := range []string{
+		``,
+		`SELECT`,
+		`SELECT a`,
+		`SELECT a FROM`,
+		`SELECT a FROM t WHERE`,
+		`SELECT a FROM t WHERE a = 1.5`,
+		`SELECT a + 1 FROM t`,
+		`SELECT a FROM (SELECT b FROM t) AS x`,
+		`SELECT a FROM t UNION SELECT b FROM u`,
+		`SELECT CAST(a AS CHAR) FROM t`,
+		`SELECT 'unterminated FROM t`,
+		`SELECT a FROM t; SELECT b FROM t`,
+		`CREATE TABLE t (a GEOMETRY)`,
+		`CREATE TABLE t (a INT, PRIMARY KEY (b))`,
+		`SELECT CASE END FROM t`,
+	} {
+		t.Run(sql, func(t *testing.T) {
+			_, err := Mysql{}.Parse(sql)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+// The layouts below are the ones the formatters use to spell builtins. They are matched exactly;
+// anything else is kept as a plain function call.
+
+var mysqlTruncLayouts = map[string]ast.TimeUnit{
+	`%Y-01-01 00:00:00`: ast.TimeUnitYear,
+	`%Y-%m-01 00:00:00`: ast.TimeUnitMonth,
+	`%Y-%m-%d 00:00:00`: ast.TimeUnitDay,
+	`%Y-%m-%d %H:00:00`: ast.TimeUnitHour,
+	`%Y-%m-%d %H:%i:00`: ast.TimeUnitMinute,
+	`%Y-%m-%d %H:%i:%s`: ast.TimeUnitSecond,
+}
+
+const sqliteNowLayout = `%Y-%m-%dT%H:%M:%fZ`
+
+var sqliteExtractLayouts = map[string]ast.TimeUnit{
+	`%Y`: ast.TimeUnitYear,
+	`%m`: ast.TimeUnitMonth,
+	`%d`: ast.TimeUnitDay,
+	`%H`: ast.TimeUnitHour,
+	`%M`: ast.TimeUnitMinute,
+	`%S`: ast.TimeUnitSecond,
+}
+
+var sqliteTruncLayouts = map[string]ast.TimeUnit{
+	`%Y-01-01T00:00:00.000Z`: ast.TimeUnitYear,
+	`%Y-%m-01T00:00:00.000Z`: ast.TimeUnitMonth,
+	`%Y-%m-%dT00:00:00.000Z`: ast.TimeUnitDay,
+	`%Y-%m-%dT%H:00:00.000Z`: ast.TimeUnitHour,
+	`%Y-%m-%dT%H:%M:00.000Z`: ast.TimeUnitMinute,
+	`%Y-%m-%dT%H:%M:%S.000Z`: ast.TimeUnitSecond,
+}
+
+// translateFunction turns a call into a Builtin if it's how the dialect spells one, and into a
+// Function otherwise.
+func (p *parser) translateFunction(name string, args []ast.IntoExpr) ast.IntoExpr {
+	switch p.d {
+	case dialectMysql:
+		switch upper(name) {
+		case `CHAR_LENGTH`:
+			return ast.NewBuiltin(ast.BuiltinLength, args...)
+		case `CONCAT`:
+			return ast.NewBuiltin(ast.BuiltinConcat, args...)
+		case `NOW`:
+			if len(args) == 0 {
+				return ast.NewBuiltin(ast.BuiltinNow)
+			}
+		case `VALUES`:
+			if len(args) == 1 {
+				if id, ok := args[0].(*ast.Identifier); ok {
+					return ast.NewValuesLiteral(id)
+				}
+			}
+		}
+	case dialectSqlite:
+		switch upper(name) {
+		case `LENGTH`:
+			return ast.NewBuiltin(ast.BuiltinLength, args...)
+		case `STRFTIME`:
+			if len(args) != 2 {
+				break
+			}
+			layout, ok := stringValue(args[0])
+			if !ok {
+				break
+			}
+			if arg, ok := stringValue(args[1]); ok && layout == sqliteNowLayout && arg == `now` {
+				return ast.NewBuiltin(ast.BuiltinNow)
+			}
+			if unit, ok := sqliteTruncLayouts[layout]; ok {
+				return ast.NewBuiltin(ast.BuiltinTruncTime, args[1]).WithUnit(unit)
+			}
+		}
+	}
+	return ast.NewFunction(name, args...)
+}
+
+// translateCast handles the casts the formatters use to spell builtins. The AST has no general CAST.
+func (p *parser) translateCast(pos int, e ast.IntoExpr, typ string) ast.IntoExpr {
+	if fn, ok := e.(*ast.Function); ok && len(fn.Args) == 2 {
+		switch {
+		case p.d == dialectMysql && strings.EqualFold(fn.Name, `DATE_FORMAT`) && strings.EqualFold(typ, `DATETIME`):
+			layout, _ := stringValue(fn.Args[1])
+			if unit, ok := mysqlTruncLayouts[layout]; ok {
+				return &ast.Builtin{Func: ast.BuiltinTruncTime, Args: fn.Args[:1], Unit: unit}
+			}
+		case p.d == dialectSqlite && strings.EqualFold(fn.Name, `STRFTIME`) && strings.EqualFold(typ, `INTEGER`):
+			layout, _ := stringValue(fn.Args[0])
+			if unit, ok := sqliteExtractLayouts[layout]; ok {
+				return &ast.Builtin{Func: ast.BuiltinExtract, Args: fn.Args[1:], Unit: unit}
+			}
+		}
+	}
+
+	failf(pos, `unsupported CAST to %s`, typ)
+	return nil
+}
+
+// identifier translates SQLite's excluded.col, which refers to the value that would have been inserted
+// in an upsert, into the AST's ValuesLiteral.
+func (p *parser) identifier(name string) ast.IntoExpr {
+	if p.d == dialectSqlite {
+		if col, ok := cutPrefixFold(name, `excluded.`); ok {
+			return ast.NewValuesLiteral(ast.NewIdentifier(col))
+		}
+	}
+	return ast.NewIdentifier(name)
+}
+
+func stringValue(e any) (string, bool) {
+	if s, ok := e.(*ast.StringLiteral); ok {
+		return s.Value, true
+	}
+	return ``, false
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
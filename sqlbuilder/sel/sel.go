@@ -12,16 +12,25 @@ import (
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/dispatch"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/limit"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/statement"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/window"
 )
 
 type Formatter interface {
-	FormatNode(w io.Writer, n ast.Node)
+	FormatNode(w io.Writer, n ast.Node) error
+}
+
+type namedWindow struct {
+	name   string
+	window *window.Builder
 }
 
 type Builder struct {
 	tableExpr ast.IntoTableExpr
 	forUpdate bool
 	orderBy   *filter.Order
+	groupBy   []ast.IntoExpr
+	having    filter.Filter
+	windows   []namedWindow
 
 	exprs []ast.IntoExpr
 
@@ -29,6 +38,7 @@ type Builder struct {
 	*limit.LimitBuilder[*Builder]
 
 	formatter Formatter
+	hooks     []dispatch.Hook
 }
 
 func NewBuilder(f Formatter, tableExpr ast.IntoTableExpr) *Builder {
@@ -54,6 +64,40 @@ func (b *Builder) Expressions(exprs ...ast.IntoExpr) *Builder {
 	return b
 }
 
+func (b *Builder) GroupBy(colNames ...string) *Builder {
+	for _, c := range colNames {
+		b.groupBy = append(b.groupBy, ast.NewIdentifier(c))
+	}
+	return b
+}
+
+func (b *Builder) GroupByExpressions(exprs ...ast.IntoExpr) *Builder {
+	b.groupBy = append(b.groupBy, exprs...)
+	return b
+}
+
+// Having filters groups. Aliases from the select list can be referred to by name, e.g.
+// Having(filter.Greater("n", 1)) after selecting functions.CountAll().As("n").
+func (b *Builder) Having(f filter.Filter) *Builder {
+	b.having = f
+	return b
+}
+
+// As aliases this query when it's used as an expression (a scalar subquery) in a select list.
+func (b *Builder) As(alias string) ast.IntoExpr {
+	return ast.NewAlias(b, alias)
+}
+
+// Window declares a named window in the WINDOW clause, which window functions can refer to with
+// OverWindow.
+func (b *Builder) Window(name string, w *window.Builder) *Builder {
+	b.windows = append(b.windows, namedWindow{
+		name:   name,
+		window: w,
+	})
+	return b
+}
+
 func (b *Builder) ForUpdate() *Builder {
 	b.forUpdate = true
 	return b
@@ -64,43 +108,73 @@ func (b *Builder) OrderBy(o filter.Order) *Builder {
 	return b
 }
 
-func (b *Builder) Build() (statement.Statement, error) {
+// IntoSelect returns the AST for this query, so that it can be embedded in other statements (e.g.
+// CREATE TABLE ... AS SELECT).
+func (b *Builder) IntoSelect() *ast.Select {
 	n := ast.NewSelect(b.tableExpr.IntoTableExpr(), b.exprs...)
 
 	n.WithWhere(b.ConditionBuilder)
+	n.WithGroupBy(b.groupBy...)
+	if b.having != nil {
+		n.WithHaving(b.having)
+	}
+
+	for _, w := range b.windows {
+		n.WithWindow(w.name, w.window.IntoSpec())
+	}
 
 	offset, limit := b.LimitBuilder.OffsetAndLimit()
 	n.WithLimit(offset, limit)
 
 	if b.orderBy != nil {
-		n.WithOrders(ast.NewOrder(ast.NewIdentifier(b.orderBy.Column), b.orderBy.Direction.ToASTDirection()))
+		n.WithOrders(ast.NewOrder(b.orderBy.ToASTExpr(), b.orderBy.Direction.ToASTDirection()))
 	}
 
 	if b.forUpdate {
 		n.WithLock(ast.ForUpdateLock)
 	}
 
+	return n
+}
+
+// IntoExpr returns this query as a subquery, so that it can be used as an expression (e.g. with
+// filter.ExprIn).
+func (b *Builder) IntoExpr() ast.Expr {
+	return ast.NewSubquery(b.IntoSelect())
+}
+
+func (b *Builder) Build() (statement.Statement, error) {
+	n := b.IntoSelect()
+
 	sb := &strings.Builder{}
-	b.formatter.FormatNode(sb, n)
+	if err := b.formatter.FormatNode(sb, n); err != nil {
+		return statement.Statement{}, err
+	}
 
 	return statement.Statement{
 		Stmt: sb.String(),
-		Args: ast.GetArgs(n),
+		Args: ast.GetArgsFor(b.formatter, n),
 	}, nil
 }
 
+// WithHooks adds hooks, which wrap each statement the builder runs.
+func (b *Builder) WithHooks(hs ...dispatch.Hook) *Builder {
+	b.hooks = append(b.hooks, hs...)
+	return b
+}
+
 func (b *Builder) Query(q dispatch.Queryer) (*sql.Rows, error) {
-	return dispatch.Query(b, q)
+	return dispatch.Query(b, q, b.hooks...)
 }
 
 func (b *Builder) QueryContext(ctx context.Context, q dispatch.QueryCtxer) (*sql.Rows, error) {
-	return dispatch.QueryContext(ctx, b, q)
+	return dispatch.QueryContext(ctx, b, q, b.hooks...)
 }
 
 func (b *Builder) QueryRow(q dispatch.RowQueryer) (*sql.Row, error) {
-	return dispatch.QueryRow(b, q)
+	return dispatch.QueryRow(b, q, b.hooks...)
 }
 
 func (b *Builder) QueryRowContext(ctx context.Context, q dispatch.RowQueryCtxer) (*sql.Row, error) {
-	return dispatch.QueryRowContext(ctx, b, q)
+	return dispatch.QueryRowContext(ctx, b, q, b.hooks...)
 }
@@ -0,0 +1,190 @@
+package statement
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/scan"
+)
+
+// Fingerprint identifies the shape of a statement, independent of its arguments.
+type Fingerprint struct {
+	// Normalized is the statement with every literal and placeholder replaced by ?, keywords
+	// uppercased, whitespace and comments canonicalized, and lists of values collapsed, so that e.g.
+	// IN (?,?) and IN (?,?,?) have the same form. Identifiers and aliases are kept as written.
+	Normalized string
+	// Hash is a short, stable hash of Normalized.
+	Hash string
+}
+
+// Fingerprint normalizes the statement so that statements which differ only in their arguments, or in
+// how many of them there are, compare equal. It's meant for grouping metrics and logs by query; two
+// statements with the same fingerprint aren't necessarily equivalent.
+func (s Statement) Fingerprint() Fingerprint {
+	toks := fingerprintTokens(s.Stmt)
+	items, _ := fingerprintGroup(toks, 0)
+	normalized := renderFingerprintItems(items)
+
+	sum := sha256.Sum256([]byte(normalized))
+	return Fingerprint{
+		Normalized: normalized,
+		Hash:       hex.EncodeToString(sum[:8]),
+	}
+}
+
+type fpKind int
+
+const (
+	fpWord fpKind = iota
+	fpQuoted
+	// fpValue is a placeholder or a literal.
+	fpValue
+	fpPunct
+	// fpGroup is a parenthesized list of items, already rendered.
+	fpGroup
+)
+
+type fpItem struct {
+	kind fpKind
+	text string
+	// spaced is whether whitespace preceded the item in the statement. It's only used to tell
+	// function calls, e.g. COUNT(x), from keywords and table names, e.g. IN (x).
+	spaced bool
+}
+
+// fingerprintTokens splits sql into items. The statement's dialect isn't known, so it's scanned with
+// scan.Any.
+func fingerprintTokens(sql string) []fpItem {
+	var toks []fpItem
+	spaced := false
+	for _, t := range scan.Tokens(sql, scan.Any) {
+		switch t.Kind {
+		case scan.Space, scan.LineComment, scan.BlockComment:
+			spaced = true
+			continue
+		case scan.String, scan.Placeholder, scan.Number:
+			toks = append(toks, fpItem{kind: fpValue, text: `?`, spaced: spaced})
+		case scan.QuotedIdent:
+			toks = append(toks, fpItem{kind: fpQuoted, text: t.Text, spaced: spaced})
+		case scan.Word:
+			word := t.Text
+			if upper := strings.ToUpper(word); fingerprintKeywords[upper] {
+				word = upper
+			}
+			toks = append(toks, fpItem{kind: fpWord, text: word, spaced: spaced})
+		default:
+			toks = append(toks, fpItem{kind: fpPunct, text: t.Text, spaced: spaced})
+		}
+		spaced = false
+	}
+	return toks
+}
+
+// fingerprintGroup normalizes the tokens up to the ) closing the current group, or the end of the
+// tokens, and returns them along with the index of the next token.
+func fingerprintGroup(toks []fpItem, i int) ([]fpItem, int) {
+	var items []fpItem
+	for i < len(toks) {
+		t := toks[i]
+		i++
+		switch {
+		case t.kind == fpPunct && t.text == `)`:
+			return collapseRepeats(items), i
+		case t.kind == fpPunct && t.text == `(`:
+			var inner []fpItem
+			inner, i = fingerprintGroup(toks, i)
+			if n := len(items); n > 0 && items[n-1].kind == fpWord && !t.spaced {
+				// Function names are case insensitive.
+				items[n-1].text = strings.ToUpper(items[n-1].text)
+			}
+			items = append(items, fpItem{
+				kind:   fpGroup,
+				text:   `(` + renderGroupContents(inner) + `)`,
+				spaced: t.spaced,
+			})
+		default:
+			items = append(items, t)
+		}
+	}
+	return collapseRepeats(items), i
+}
+
+// renderGroupContents renders the inside of a group. A list of values of any length, like the ones in
+// IN (?,?,?) or VALUES (?,?), is rendered as ?+.
+func renderGroupContents(items []fpItem) string {
+	if len(items) == 0 {
+		return ``
+	}
+	for i, it := range items {
+		if i%2 == 0 && it.kind != fpValue || i%2 == 1 && it.text != `,` {
+			return renderFingerprintItems(items)
+		}
+	}
+	if len(items)%2 == 0 {
+		return renderFingerprintItems(items)
+	}
+	return `?+`
+}
+
+// collapseRepeats collapses runs of identical groups separated by commas or ORs, like the rows of a
+// multi-row insert, or SQLite's expansion of a tuple IN, into a single group.
+func collapseRepeats(items []fpItem) []fpItem {
+	var res []fpItem
+	for _, it := range items {
+		n := len(res)
+		if it.kind == fpGroup && n >= 2 &&
+			res[n-2].kind == fpGroup && res[n-2].text == it.text &&
+			(res[n-1].text == `,` || res[n-1].text == `OR`) {
+			res = res[:n-1]
+			continue
+		}
+		res = append(res, it)
+	}
+	return res
+}
+
+func renderFingerprintItems(items []fpItem) string {
+	sb := &strings.Builder{}
+	for i, it := range items {
+		if i > 0 && needsSpace(items[i-1], it) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(it.text)
+	}
+	return sb.String()
+}
+
+func needsSpace(prev, cur fpItem) bool {
+	switch {
+	case cur.kind == fpPunct && (cur.text == `,` || cur.text == `.`):
+		return false
+	case prev.kind == fpPunct && prev.text == `.`:
+		return false
+	case cur.kind == fpGroup && (prev.kind == fpWord || prev.kind == fpQuoted) && !fingerprintKeywords[prev.text]:
+		// Keep function calls and table names apart, e.g. COUNT(*) and INSERT INTO t (a).
+		return cur.spaced
+	}
+	return true
+}
+
+// fingerprintKeywords are uppercased when normalizing, as are function names. Other words are
+// identifiers, which may be case sensitive, so they are kept as written.
+var fingerprintKeywords = map[string]bool{
+	`ADD`: true, `ALL`: true, `ALTER`: true, `AND`: true, `AS`: true, `ASC`: true,
+	`AUTO_INCREMENT`: true, `AUTOINCREMENT`: true, `BETWEEN`: true, `BY`: true, `CASE`: true,
+	`CAST`: true, `CHARSET`: true, `COLLATE`: true, `COLUMN`: true, `COMMENT`: true, `CONFLICT`: true,
+	`CREATE`: true, `CROSS`: true, `CURRENT`: true, `CURRENT_TIMESTAMP`: true, `DEFAULT`: true,
+	`DELETE`: true, `DESC`: true, `DISTINCT`: true, `DO`: true, `DROP`: true, `DUPLICATE`: true,
+	`ELSE`: true, `END`: true, `ENGINE`: true, `ESCAPE`: true, `EXISTS`: true, `FALSE`: true,
+	`FOLLOWING`: true, `FOR`: true, `FROM`: true, `FULL`: true, `GENERATED`: true, `GROUP`: true,
+	`HAVING`: true, `IF`: true, `IN`: true, `INNER`: true, `INSERT`: true, `INTO`: true, `IS`: true,
+	`JOIN`: true, `KEY`: true, `LEFT`: true, `LIKE`: true, `LIMIT`: true, `LOCK`: true, `MODE`: true,
+	`NOT`: true, `NULL`: true, `OFFSET`: true, `ON`: true, `OR`: true, `ORDER`: true, `OUTER`: true,
+	`OVER`: true, `PARTITION`: true, `PRECEDING`: true, `PRIMARY`: true, `RANGE`: true,
+	`REPLACE`: true, `RIGHT`: true, `ROW`: true, `ROWID`: true, `ROWS`: true, `SELECT`: true,
+	`SET`: true, `SHARE`: true, `STORED`: true, `STRICT`: true, `TABLE`: true, `THEN`: true,
+	`TRUE`: true, `UNBOUNDED`: true, `UNION`: true, `UPDATE`: true, `USING`: true, `VALUES`: true,
+	`VIEW`: true, `VIRTUAL`: true, `WHEN`: true, `WHERE`: true, `WINDOW`: true, `WITH`: true,
+	`WITHOUT`: true,
+}
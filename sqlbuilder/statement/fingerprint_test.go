@@ -0,0 +1,65 @@
+package statement
+
+import (
+	"testing"
+
+	"github.com/cszczepaniak/gotest/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		stmts []string
+		exp   string
+	}{{
+		stmts: []string{
+			`SELECT a FROM t WHERE b IN (?,?) AND c = ?`,
+			`SELECT a FROM t WHERE b IN (?) AND c = ?`,
+			"select a\n  from t -- a comment\n  where b in (1, 2, 3) /* another */ and c = 'it''s'",
+		},
+		exp: `SELECT a FROM t WHERE b IN (?+) AND c = ?`,
+	}, {
+		stmts: []string{
+			`INSERT INTO t (a,b) VALUES (?,?),(?,?),(?,?)`,
+			`INSERT INTO t (a,b) VALUES (?,?)`,
+		},
+		exp: `INSERT INTO t (a, b) VALUES (?+)`,
+	}, {
+		stmts: []string{
+			`SELECT a FROM t WHERE (a,b) IN ((?,?),(?,?))`,
+			`SELECT a FROM t WHERE (a,b) IN ((?,?))`,
+		},
+		exp: `SELECT a FROM t WHERE (a, b) IN ((?+))`,
+	}, {
+		stmts: []string{
+			`SELECT a FROM t WHERE ((a = ? AND b = ?) OR (a = ? AND b = ?))`,
+			`SELECT a FROM t WHERE ((a = ? AND b = ?))`,
+		},
+		exp: `SELECT a FROM t WHERE ((a = ? AND b = ?))`,
+	}, {
+		stmts: []string{
+			"SELECT COUNT(*) AS n, t.`b` FROM t AS x LIMIT ?, ?",
+			"select count(*) as n,t.`b` from t as x limit 10,5",
+		},
+		exp: "SELECT COUNT(*) AS n, t.`b` FROM t AS x LIMIT ?, ?",
+	}, {
+		stmts: []string{
+			`SELECT a FROM t WHERE b = ? AND c = 1.5`,
+			`SELECT a FROM t WHERE b = 'it\'s (?' AND c = ? /* d = ? */`,
+		},
+		exp: `SELECT a FROM t WHERE b = ? AND c = ?`,
+	}}
+
+	for _, tc := range tests {
+		first := Statement{Stmt: tc.stmts[0]}.Fingerprint()
+		for _, stmt := range tc.stmts {
+			fp := Statement{Stmt: stmt}.Fingerprint()
+			assert.Equal(t, fp.Normalized, tc.exp)
+			assert.Equal(t, fp.Hash, first.Hash)
+		}
+	}
+
+	// Aliases and identifiers are part of the shape.
+	a := Statement{Stmt: `SELECT a AS x FROM t`}.Fingerprint()
+	b := Statement{Stmt: `SELECT a AS y FROM t`}.Fingerprint()
+	assert.Equal(t, a.Hash == b.Hash, false)
+}
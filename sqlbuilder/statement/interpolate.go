@@ -0,0 +1,50 @@
+package statement
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/scan"
+)
+
+// Dialect renders argument values as SQL literals. The formatters (e.g. formatter.Mysql) implement it,
+// and also tell Interpolate how their SQL quotes strings, so that a ? inside one isn't taken for a
+// placeholder.
+type Dialect interface {
+	// Literal renders v, which is one of the types a driver.Value can hold, as a literal.
+	Literal(v driver.Value) (string, error)
+}
+
+// Interpolate returns the statement with every placeholder replaced by its argument, rendered as a
+// literal of the given dialect. Arguments are converted the way database/sql converts them, so
+// driver.Valuers, pointers and named types work.
+//
+// The result is for humans: logs, error messages, pasting into a SQL console. It is NOT safe to
+// execute. Escaping depends on server settings the dialect can't see, so always run the statement with
+// its Args instead.
+func (s Statement) Interpolate(d Dialect) (string, error) {
+	frags := scan.SplitPlaceholders(s.Stmt, scan.Of(d))
+	if len(frags)-1 != len(s.Args) {
+		return ``, fmt.Errorf(`statement has %d placeholders but %d arguments`, len(frags)-1, len(s.Args))
+	}
+
+	sb := &strings.Builder{}
+	for i, frag := range frags {
+		sb.WriteString(frag)
+		if i == len(s.Args) {
+			break
+		}
+
+		v, err := driver.DefaultParameterConverter.ConvertValue(s.Args[i])
+		if err != nil {
+			return ``, fmt.Errorf(`converting argument %d: %w`, i, err)
+		}
+		lit, err := d.Literal(v)
+		if err != nil {
+			return ``, fmt.Errorf(`rendering argument %d: %w`, i, err)
+		}
+		sb.WriteString(lit)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,271 @@
+// Package stmtcache prepares statements once and reuses them. A Cache can be passed to any builder's
+// Exec or Query methods in place of the *sql.DB it wraps, and its Tx method returns a view to use in
+// place of a transaction:
+//
+//	cache := stmtcache.New(db, 100)
+//	rows, err := b.SelectFrom(t).Where(filter.Equals(`ID`, id)).Query(cache)
+//	_, err = b.DeleteFrom(t).Where(filter.Equals(`ID`, id)).Exec(cache.Tx(tx))
+package stmtcache
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// DB prepares statements. *sql.DB and *sql.Conn implement it. Transactions implement it too, but their
+// statements can't be used once they end, so use Cache.Tx instead.
+type DB interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Cache keeps up to a fixed number of prepared statements, keyed by their SQL, evicting the least
+// recently used one when it's full. It's safe for concurrent use.
+type Cache struct {
+	db   DB
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru holds *entry, most recently used first.
+	lru *list.List
+}
+
+type entry struct {
+	query string
+	stmt  *sql.Stmt
+	// refs counts the calls using stmt. An evicted statement is closed once it's no longer in use.
+	refs    int
+	evicted bool
+}
+
+// New returns a cache which prepares statements on db and keeps at most size of them.
+func New(db DB, size int) *Cache {
+	return &Cache{
+		db:      db,
+		size:    max(size, 1),
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Len returns the number of statements currently prepared.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// Close closes every prepared statement. Statements in use are closed once their calls finish. The
+// cache can still be used afterwards; it prepares statements again as they're needed.
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for c.lru.Len() > 0 {
+		errs = append(errs, c.evict(c.lru.Back()))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cache) Exec(query string, args ...any) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c *Cache) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	e, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(e)
+
+	res, err := e.stmt.ExecContext(ctx, args...)
+	c.checkErr(e, err)
+	return res, err
+}
+
+func (c *Cache) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+func (c *Cache) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	e, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// Rows keep the statement open until they're closed, so it's safe to release it right away.
+	defer c.release(e)
+
+	rows, err := e.stmt.QueryContext(ctx, args...)
+	c.checkErr(e, err)
+	return rows, err
+}
+
+func (c *Cache) QueryRow(query string, args ...any) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+func (c *Cache) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	e, err := c.acquire(ctx, query)
+	if err != nil {
+		// A Row can't be made from an error, so let the database report it.
+		return c.db.QueryRowContext(ctx, query, args...)
+	}
+	defer c.release(e)
+
+	row := e.stmt.QueryRowContext(ctx, args...)
+	c.checkErr(e, row.Err())
+	return row
+}
+
+// acquire returns the entry for query, preparing it if necessary. The caller must release it.
+func (c *Cache) acquire(ctx context.Context, query string) (*entry, error) {
+	c.mu.Lock()
+	if el, ok := c.entries[query]; ok {
+		c.lru.MoveToFront(el)
+		e := el.Value.(*entry)
+		e.refs++
+		c.mu.Unlock()
+		return e, nil
+	}
+	c.mu.Unlock()
+
+	// Prepare without holding the lock, so that a slow prepare doesn't hold up other queries.
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[query]; ok {
+		// Someone else prepared it in the meantime.
+		_ = stmt.Close()
+		c.lru.MoveToFront(el)
+		e := el.Value.(*entry)
+		e.refs++
+		return e, nil
+	}
+
+	e := &entry{query: query, stmt: stmt, refs: 1}
+	c.entries[query] = c.lru.PushFront(e)
+	for c.lru.Len() > c.size {
+		_ = c.evict(c.lru.Back())
+	}
+	return e, nil
+}
+
+func (c *Cache) release(e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.refs--
+	if e.evicted && e.refs == 0 {
+		_ = e.stmt.Close()
+	}
+}
+
+// checkErr evicts e if err means its statement can't be used again, because its connection is gone.
+func (c *Cache) checkErr(e *entry, err error) {
+	if !isStale(err) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[e.query]; ok && el.Value == e {
+		_ = c.evict(el)
+	}
+}
+
+// evict removes el from the cache, closing its statement unless it's in use. c.mu must be held.
+func (c *Cache) evict(el *list.Element) error {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.entries, e.query)
+	e.evicted = true
+	if e.refs == 0 {
+		return e.stmt.Close()
+	}
+	return nil
+}
+
+func isStale(err error) bool {
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone)
+}
+
+// Tx returns a view of the cache for use within tx, which must have been started on the cache's
+// database. It runs the cache's statements on tx (see sql.Tx.StmtContext), so they're prepared once
+// rather than in every transaction. Once tx ends, the view fails with sql.ErrTxDone.
+func (c *Cache) Tx(tx *sql.Tx) *TxView {
+	return &TxView{c: c, tx: tx}
+}
+
+// TxView runs a Cache's statements within a transaction. It's safe for concurrent use, as far as tx is.
+type TxView struct {
+	c  *Cache
+	tx *sql.Tx
+}
+
+func (v *TxView) Exec(query string, args ...any) (sql.Result, error) {
+	return v.ExecContext(context.Background(), query, args...)
+}
+
+func (v *TxView) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, err := v.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (v *TxView) Query(query string, args ...any) (*sql.Rows, error) {
+	return v.QueryContext(context.Background(), query, args...)
+}
+
+func (v *TxView) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := v.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// Like the cache's own statements, this one stays open until the rows are closed.
+	defer stmt.Close()
+
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (v *TxView) QueryRow(query string, args ...any) *sql.Row {
+	return v.QueryRowContext(context.Background(), query, args...)
+}
+
+func (v *TxView) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := v.stmt(ctx, query)
+	if err != nil {
+		return v.tx.QueryRowContext(ctx, query, args...)
+	}
+	defer stmt.Close()
+
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// stmt returns the cache's statement for query, bound to the transaction. The caller must close it.
+func (v *TxView) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	e, err := v.c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer v.c.release(e)
+
+	// The transaction reports whether it's done: a statement bound to a finished one fails with
+	// sql.ErrTxDone, without touching the cache's statement.
+	return v.tx.StmtContext(ctx, e.stmt), nil
+}
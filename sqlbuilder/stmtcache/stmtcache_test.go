@@ -0,0 +1,164 @@
+package stmtcache
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cszczepaniak/gotest/assert"
+)
+
+// fakeDriver accepts any statement, and counts the statements which are still open.
+type fakeDriver struct {
+	open atomic.Int64
+}
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return fakeConn{d: d}, nil }
+func (d *fakeDriver) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	c.d.open.Add(1)
+	return fakeStmt{d: c.d}, nil
+}
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	d *fakeDriver
+}
+
+func (s fakeStmt) Close() error {
+	s.d.open.Add(-1)
+	return nil
+}
+func (s fakeStmt) NumInput() int                              { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New(`not implemented`)
+}
+
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	t.Helper()
+
+	d := &fakeDriver{}
+	db := sql.OpenDB(d)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+	return db, d
+}
+
+func TestEvictInUse(t *testing.T) {
+	db, d := openFakeDB(t)
+	c := New(db, 1)
+	ctx := context.Background()
+
+	inUse, err := c.acquire(ctx, `a`)
+	assert.NoError(t, err)
+
+	// Preparing b evicts a, but it's still in use, so it's left open.
+	b, err := c.acquire(ctx, `b`)
+	assert.NoError(t, err)
+	c.release(b)
+	assert.Equal(t, c.Len(), 1)
+	assert.Equal(t, inUse.evicted, true)
+	assert.Equal(t, d.open.Load(), int64(2))
+
+	_, err = inUse.stmt.ExecContext(ctx)
+	assert.NoError(t, err)
+
+	// Once it's released, it's closed.
+	c.release(inUse)
+	assert.Equal(t, d.open.Load(), int64(1))
+	_, err = inUse.stmt.ExecContext(ctx)
+	assert.Error(t, err)
+
+	assert.NoError(t, c.Close())
+	assert.Equal(t, d.open.Load(), int64(0))
+}
+
+// flakyDB fails the first prepare once release is closed, letting the others through.
+type flakyDB struct {
+	*sql.DB
+
+	preparing chan struct{}
+	release   chan struct{}
+	calls     atomic.Int64
+}
+
+var errPrepare = errors.New(`prepare failed`)
+
+func (db *flakyDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if db.calls.Add(1) == 1 {
+		close(db.preparing)
+		<-db.release
+		return nil, errPrepare
+	}
+	return db.DB.PrepareContext(ctx, query)
+}
+
+func TestConcurrentPrepareFails(t *testing.T) {
+	sqlDB, d := openFakeDB(t)
+	db := &flakyDB{DB: sqlDB, preparing: make(chan struct{}), release: make(chan struct{})}
+	c := New(db, 10)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := c.ExecContext(ctx, `a`)
+		assert.Equal(t, errors.Is(err, errPrepare), true)
+	}()
+
+	<-db.preparing
+	_, err := c.ExecContext(ctx, `a`)
+	assert.NoError(t, err)
+
+	close(db.release)
+	wg.Wait()
+
+	// The failure doesn't disturb the statement the other call prepared.
+	assert.Equal(t, c.Len(), 1)
+	_, err = c.ExecContext(ctx, `a`)
+	assert.NoError(t, err)
+	assert.Equal(t, db.calls.Load(), int64(2))
+	assert.Equal(t, d.open.Load(), int64(1))
+}
+
+func TestTxView(t *testing.T) {
+	db, d := openFakeDB(t)
+	c := New(db, 10)
+	ctx := context.Background()
+
+	_, err := c.ExecContext(ctx, `a`)
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	v := c.Tx(tx)
+
+	// The transaction runs on the connection the statement was prepared on, so it's reused.
+	_, err = v.ExecContext(ctx, `a`)
+	assert.NoError(t, err)
+	assert.Equal(t, d.open.Load(), int64(1))
+	assert.NoError(t, tx.Commit())
+
+	// A finished transaction is reported as such, and the cache's statement is kept.
+	_, err = v.ExecContext(ctx, `a`)
+	assert.Equal(t, errors.Is(err, sql.ErrTxDone), true)
+	assert.Equal(t, c.Len(), 1)
+	_, err = c.ExecContext(ctx, `a`)
+	assert.NoError(t, err)
+}
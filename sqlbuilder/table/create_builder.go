@@ -3,28 +3,39 @@ package table
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"io"
 	"strings"
 
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/column"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/dispatch"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/sel"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/statement"
 )
 
-type columnBuilder interface {
-	Build() *ast.ColumnSpec
+type Formatter interface {
+	FormatNode(w io.Writer, n ast.Node) error
 }
 
-type Formatter interface {
-	FormatNode(w io.Writer, n ast.Node)
+// likeEmulator is implemented by formatters for dialects without CREATE TABLE ... LIKE, which need the
+// other table's definition to emulate it.
+type likeEmulator interface {
+	EmulatesCreateLike() bool
 }
 
 type CreateBuilder struct {
 	f Formatter
 
 	name              string
-	columns           []columnBuilder
+	columns           []column.Builder
 	createIfNotExists bool
+	options           []*ast.TableOption
+
+	asSelect *sel.Builder
+	like     BareTableRef
+
+	hooks []dispatch.Hook
 }
 
 func NewCreateBuilder(f Formatter, name string) *CreateBuilder {
@@ -39,12 +50,82 @@ func (b *CreateBuilder) IfNotExists() *CreateBuilder {
 	return b
 }
 
-func (b *CreateBuilder) Columns(cs ...columnBuilder) *CreateBuilder {
+func (b *CreateBuilder) Columns(cs ...column.Builder) *CreateBuilder {
 	b.columns = append(b.columns, cs...)
 	return b
 }
 
+func (b *CreateBuilder) addOption(kind ast.TableOptionKind, val string) *CreateBuilder {
+	b.options = append(b.options, ast.NewTableOption(kind, val))
+	return b
+}
+
+// Engine sets the table's storage engine (MySQL only).
+func (b *CreateBuilder) Engine(engine string) *CreateBuilder {
+	return b.addOption(ast.TableOptionEngine, engine)
+}
+
+// DefaultCharset sets the table's default character set, e.g. utf8mb4 (MySQL only).
+func (b *CreateBuilder) DefaultCharset(charset string) *CreateBuilder {
+	return b.addOption(ast.TableOptionCharset, charset)
+}
+
+// Collate sets the table's default collation, e.g. utf8mb4_unicode_ci (MySQL only). SQLite
+// collations can be set per column with column.*(...).Collate.
+func (b *CreateBuilder) Collate(collation string) *CreateBuilder {
+	return b.addOption(ast.TableOptionCollate, collation)
+}
+
+// Comment attaches a comment to the table (MySQL only).
+func (b *CreateBuilder) Comment(comment string) *CreateBuilder {
+	return b.addOption(ast.TableOptionComment, comment)
+}
+
+// WithoutRowID creates a WITHOUT ROWID table, which requires a primary key (SQLite only).
+func (b *CreateBuilder) WithoutRowID() *CreateBuilder {
+	return b.addOption(ast.TableOptionWithoutRowID, ``)
+}
+
+// Strict creates a STRICT table, which enforces column types (SQLite 3.37+ only).
+func (b *CreateBuilder) Strict() *CreateBuilder {
+	return b.addOption(ast.TableOptionStrict, ``)
+}
+
+// AsSelect creates the table from the result of q (CREATE TABLE ... AS SELECT). Any placeholder
+// arguments in q are passed through to the statement.
+func (b *CreateBuilder) AsSelect(q *sel.Builder) *CreateBuilder {
+	b.asSelect = q
+	return b
+}
+
+// LikeTable creates the table with the same definition as ref (CREATE TABLE ... LIKE). SQLite has no
+// LIKE, so it is emulated by copying ref's definition from sqlite_master; this only works through
+// Exec/ExecContext, since reading the definition requires a database connection, and unlike in MySQL,
+// ref's indexes aren't copied.
+func (b *CreateBuilder) LikeTable(ref BareTableRef) *CreateBuilder {
+	b.like = ref
+	return b
+}
+
 func (b *CreateBuilder) Build() (statement.Statement, error) {
+	return b.build(``)
+}
+
+func (b *CreateBuilder) build(likeDefinition string) (statement.Statement, error) {
+	ct := b.node(likeDefinition)
+
+	sb := &strings.Builder{}
+	if err := b.f.FormatNode(sb, ct); err != nil {
+		return statement.Statement{}, err
+	}
+
+	return statement.Statement{
+		Stmt: sb.String(),
+		Args: ast.GetArgsFor(b.f, ct),
+	}, nil
+}
+
+func (b *CreateBuilder) node(likeDefinition string) *ast.CreateTable {
 	ct := ast.NewCreateTable(b.name)
 	if b.createIfNotExists {
 		ct.CreateIfNotExists()
@@ -53,19 +134,96 @@ func (b *CreateBuilder) Build() (statement.Statement, error) {
 	for _, col := range b.columns {
 		ct.AddColumn(col.Build())
 	}
+	for _, opt := range b.options {
+		ct.AddOption(opt)
+	}
+	if b.asSelect != nil {
+		ct.AsSelect = b.asSelect.IntoSelect()
+	}
+	if b.like != nil {
+		ct.Like = ast.NewTableName(ast.BaseTableName(b.like.IntoTableExpr()))
+		ct.LikeDefinition = likeDefinition
+	}
+	return ct
+}
 
-	sb := &strings.Builder{}
-	b.f.FormatNode(sb, ct)
-
-	return statement.Statement{
-		Stmt: sb.String(),
-	}, nil
+// WithHooks adds hooks, which wrap each statement the builder runs.
+func (b *CreateBuilder) WithHooks(hs ...dispatch.Hook) *CreateBuilder {
+	b.hooks = append(b.hooks, hs...)
+	return b
 }
 
 func (b *CreateBuilder) Exec(e dispatch.Execer) (sql.Result, error) {
-	return dispatch.Exec(b, e)
+	var queryRow func(string, ...any) *sql.Row
+	if q, ok := e.(dispatch.RowQueryer); ok {
+		queryRow = q.QueryRow
+	}
+
+	sb, err := b.resolveLike(queryRow)
+	if err != nil {
+		return nil, err
+	}
+	return dispatch.Exec(sb, e, b.hooks...)
 }
 
 func (b *CreateBuilder) ExecContext(ctx context.Context, e dispatch.ExecCtxer) (sql.Result, error) {
-	return dispatch.ExecContext(ctx, b, e)
+	var queryRow func(string, ...any) *sql.Row
+	if q, ok := e.(dispatch.RowQueryCtxer); ok {
+		queryRow = func(stmt string, args ...any) *sql.Row {
+			return q.QueryRowContext(ctx, stmt, args...)
+		}
+	}
+
+	sb, err := b.resolveLike(queryRow)
+	if err != nil {
+		return nil, err
+	}
+	return dispatch.ExecContext(ctx, sb, e, b.hooks...)
+}
+
+type statementBuilder interface {
+	Build() (statement.Statement, error)
+}
+
+// resolveLike returns the builder to execute. When the table is created LIKE another with a formatter
+// which can't express that on its own (like the SQLite one), the other table's definition is read from
+// sqlite_master with queryRow so that the formatter can emulate LIKE. Otherwise the builder is executed
+// as is, and reports its own errors.
+func (b *CreateBuilder) resolveLike(queryRow func(string, ...any) *sql.Row) (statementBuilder, error) {
+	if b.like == nil || queryRow == nil {
+		return b, nil
+	}
+	if e, ok := b.f.(likeEmulator); !ok || !e.EmulatesCreateLike() {
+		return b, nil
+	}
+	if err := ast.Validate(b.node(``)); err != nil {
+		return nil, err
+	}
+
+	// The table may be qualified by its schema (e.g. with sqlbuilder.Builder.SetDatabase), which has its
+	// own sqlite_master listing the tables by their bare names.
+	master := `sqlite_master`
+	name := ast.BaseTableName(b.like.IntoTableExpr())
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		schema := name[:i]
+		name = name[i+1:]
+		master = `"` + strings.ReplaceAll(schema, `"`, `""`) + `".sqlite_master`
+	}
+
+	var def string
+	row := queryRow(`SELECT sql FROM `+master+` WHERE type = 'table' AND name = ?`, name)
+	if err := row.Scan(&def); err != nil {
+		return nil, fmt.Errorf(`reading the definition of %s: %w`, ast.BaseTableName(b.like.IntoTableExpr()), err)
+	}
+
+	return likeBuilder{b: b, definition: def}, nil
+}
+
+type likeBuilder struct {
+	b          *CreateBuilder
+	definition string
+}
+
+func (lb likeBuilder) Build() (statement.Statement, error) {
+	return lb.b.build(lb.definition)
 }
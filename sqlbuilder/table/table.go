@@ -1,6 +1,9 @@
 package table
 
-import "github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+import (
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/column"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
 
 // BareTableRef is implemented only by the result of Named(name). It is accepted by
 // CreateTable so that you can pass the same table reference, but the type system
@@ -12,7 +15,8 @@ type BareTableRef interface {
 
 // BareTable represents a single table name with no alias or join. Returned by Named(name).
 type BareTable struct {
-	name string
+	name    string
+	columns []column.Builder
 }
 
 func (b *BareTable) IntoTableExpr() ast.TableExpr {
@@ -28,13 +32,51 @@ func Named(name string) *BareTable {
 	return &BareTable{name: name}
 }
 
+// WithColumns describes the table's columns to the builders that use this reference. The schema is
+// optional; when it's known, InsertInto refuses to write to generated columns.
+func (b *BareTable) WithColumns(cs ...column.Builder) *BareTable {
+	b.columns = append(b.columns, cs...)
+	return b
+}
+
+// GeneratedColumns returns the names of the generated columns among those given to WithColumns.
+func (b *BareTable) GeneratedColumns() []string {
+	return generatedColumns(b.columns)
+}
+
+func generatedColumns(cols []column.Builder) []string {
+	var names []string
+	for _, col := range cols {
+		cs := col.Build()
+		if cs.Generated != nil {
+			names = append(names, cs.Name.Name)
+		}
+	}
+	return names
+}
+
+// Qualified returns a copy of this table reference whose name is prefixed by qualifier (e.g. a
+// database name). The schema given to WithColumns is preserved.
+func (b *BareTable) Qualified(qualifier string) *BareTable {
+	if qualifier == "" {
+		return b
+	}
+	return &BareTable{
+		name:    qualifier + "." + b.name,
+		columns: b.columns,
+	}
+}
+
 // As returns a TableBuilder with an alias, for use in joins. The result does not
-// implement BareTableRef, so it cannot be passed to CreateTable.
+// implement BareTableRef, so it cannot be passed to CreateTable. The schema given to WithColumns is
+// preserved.
 func (b *BareTable) As(alias string) *TableBuilder {
-	return newTableBuilder(&ast.TableAlias{
+	tb := newTableBuilder(&ast.TableAlias{
 		ForExpr: ast.NewTableName(b.name),
 		As:      ast.NewIdentifier(alias),
 	})
+	tb.columns = b.columns
+	return tb
 }
 
 // LeftJoin starts a left join from this table.
@@ -57,6 +99,7 @@ func (b *BareTable) InnerJoin(tableExpr ast.IntoTableExpr) *JoinBuilder {
 
 type TableBuilder struct {
 	tableExpr ast.IntoTableExpr
+	columns   []column.Builder
 }
 
 func newTableBuilder(tableExpr ast.IntoTableExpr) *TableBuilder {
@@ -69,6 +112,12 @@ func (tb *TableBuilder) IntoTableExpr() ast.TableExpr {
 	return tb.tableExpr.IntoTableExpr()
 }
 
+// GeneratedColumns returns the names of the generated columns among those given to the aliased
+// table's WithColumns.
+func (tb *TableBuilder) GeneratedColumns() []string {
+	return generatedColumns(tb.columns)
+}
+
 func (tb *TableBuilder) As(alias string) *TableBuilder {
 	tb.tableExpr = &ast.TableAlias{
 		ForExpr: tb.tableExpr.IntoTableExpr(),
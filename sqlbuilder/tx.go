@@ -0,0 +1,149 @@
+package sqlbuilder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// TxBeginner starts transactions. *sql.DB and *sql.Conn implement it.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Tx is a transaction started by InTx. It can be passed to any builder's Exec or Query methods.
+type Tx struct {
+	*sql.Tx
+
+	depth int
+}
+
+// RetryPolicy controls how InTx retries transactions which fail because of contention.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times to try the transaction, including the first. It defaults
+	// to 3; set it to 1 to disable retries.
+	MaxAttempts int
+	// Backoff returns how long to wait before the given retry, starting at 1. It defaults to an
+	// exponential backoff from 10ms, with jitter.
+	Backoff func(retry int) time.Duration
+	// Retryable reports whether a transaction which failed with err should be retried. It defaults to
+	// IsRetryable.
+	Retryable func(err error) bool
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return 3
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(retry)
+	}
+	// Cap the shift so that it can't overflow: 10ms<<7 is already past the 1s cap.
+	d := min(10*time.Millisecond<<min(max(retry-1, 0), 7), time.Second)
+	return d/2 + rand.N(d/2+1)
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// IsRetryable reports whether err means the transaction lost a race with another one and may succeed
+// if tried again: a MySQL deadlock (1213) or lock wait timeout (1205), or SQLITE_BUSY. The builder
+// doesn't depend on any driver, so it goes by the errors' messages; set RetryPolicy.Retryable to
+// check the driver's error types instead.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, `Error 1213`) ||
+		strings.Contains(msg, `Error 1205`) ||
+		strings.Contains(msg, `database is locked`)
+}
+
+// SetRetryPolicy sets how InTx retries transactions.
+func (b *Builder) SetRetryPolicy(p RetryPolicy) *Builder {
+	b.retryPolicy = p
+	return b
+}
+
+// InTx runs fn in a transaction, committing it if fn returns nil and rolling it back otherwise. If the
+// transaction fails with an error the retry policy considers retryable, it's rolled back and fn is run
+// again in a new transaction, so fn must not have side effects outside of the transaction.
+func (b *Builder) InTx(ctx context.Context, db TxBeginner, fn func(tx *Tx) error) error {
+	policy := b.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, fn)
+		if err == nil || attempt >= policy.maxAttempts() || !policy.retryable(err) {
+			return err
+		}
+
+		t := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+func runTx(ctx context.Context, db TxBeginner, fn func(tx *Tx) error) error {
+	sqlTx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = sqlTx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(&Tx{Tx: sqlTx}); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+	return sqlTx.Commit()
+}
+
+// InTx runs fn in a nested transaction, using a savepoint. If fn returns an error, everything it did is
+// rolled back, but the enclosing transaction carries on. Nested transactions aren't retried on their
+// own; return the error so that the outermost InTx can retry the whole transaction.
+func (tx *Tx) InTx(ctx context.Context, fn func(tx *Tx) error) error {
+	savepoint := fmt.Sprintf(`sp_%d`, tx.depth+1)
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT `+savepoint); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_, _ = tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT `+savepoint)
+			panic(r)
+		}
+	}()
+
+	if err := fn(&Tx{Tx: tx.Tx, depth: tx.depth + 1}); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT `+savepoint); rbErr != nil {
+			return fmt.Errorf(`%w (rolling back to savepoint: %w)`, err, rbErr)
+		}
+		// ROLLBACK TO keeps the savepoint; release it so the next nested transaction can reuse the name.
+		_, _ = tx.ExecContext(ctx, `RELEASE SAVEPOINT `+savepoint)
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT `+savepoint)
+	return err
+}
@@ -0,0 +1,19 @@
+package sqlbuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cszczepaniak/gotest/assert"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 1000}
+	for retry := 1; retry < p.maxAttempts(); retry++ {
+		d := p.backoff(retry)
+		assert.Equal(t, d >= 0 && d <= time.Second, true)
+	}
+
+	assert.Equal(t, p.backoff(1) <= 10*time.Millisecond, true)
+	assert.Equal(t, p.backoff(100) >= 500*time.Millisecond, true)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/column"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/condition"
 	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/dispatch"
@@ -13,12 +14,13 @@ import (
 )
 
 type Formatter interface {
-	FormatNode(w io.Writer, n ast.Node)
+	FormatNode(w io.Writer, n ast.Node) error
 }
 
 type fieldAndArg struct {
 	field string
 	arg   any
+	expr  ast.IntoExpr
 }
 
 type Builder struct {
@@ -26,6 +28,8 @@ type Builder struct {
 
 	table  ast.IntoTableExpr
 	fields []fieldAndArg
+	all    bool
+	hooks  []dispatch.Hook
 
 	*condition.ConditionBuilder[*Builder]
 }
@@ -48,6 +52,23 @@ func (b *Builder) SetFieldTo(field string, val any) *Builder {
 	return b
 }
 
+// Set sets each column to its assigned value (see column.Ref.To).
+func (b *Builder) Set(as ...column.Assignment) *Builder {
+	for _, a := range as {
+		b.SetFieldTo(a.Column, a.Value)
+	}
+	return b
+}
+
+// SetFieldToExpr sets field to an arbitrary expression, e.g. a CASE expression or a function call.
+func (b *Builder) SetFieldToExpr(field string, e ast.IntoExpr) *Builder {
+	b.fields = append(b.fields, fieldAndArg{
+		field: field,
+		expr:  e,
+	})
+	return b
+}
+
 func (b *Builder) SetFieldToNull(field string) *Builder {
 	b.fields = append(b.fields, fieldAndArg{
 		field: field,
@@ -56,15 +77,25 @@ func (b *Builder) SetFieldToNull(field string) *Builder {
 	return b
 }
 
+// All allows the update to change every row in the table. Without it, an update with no WHERE
+// condition (or a condition which is always true) fails to build.
+func (b *Builder) All() *Builder {
+	b.all = true
+	return b
+}
+
 func (b *Builder) Build() (statement.Statement, error) {
 	u := ast.NewUpdate(b.table)
 
 	exprs := make([]ast.IntoExpr, 0, len(b.fields))
 	for _, field := range b.fields {
 		var rhs ast.IntoExpr
-		if field.arg != nil {
+		switch {
+		case field.expr != nil:
+			rhs = field.expr
+		case field.arg != nil:
 			rhs = ast.NewPlaceholderLiteral(field.arg)
-		} else {
+		default:
 			rhs = ast.NewNullLiteral()
 		}
 
@@ -78,20 +109,35 @@ func (b *Builder) Build() (statement.Statement, error) {
 
 	u.AddAssignments(exprs...)
 	u.WithWhere(b.ConditionBuilder)
+	if u.Where == nil && !b.all {
+		return statement.Statement{}, &ast.BuildError{
+			Clause: `WHERE`,
+			Node:   u,
+			Reason: `refusing to update every row without a condition; use All to allow it`,
+		}
+	}
 
 	sb := strings.Builder{}
-	b.f.FormatNode(&sb, u)
+	if err := b.f.FormatNode(&sb, u); err != nil {
+		return statement.Statement{}, err
+	}
 
 	return statement.Statement{
 		Stmt: sb.String(),
-		Args: ast.GetArgs(u),
+		Args: ast.GetArgsFor(b.f, u),
 	}, nil
 }
 
+// WithHooks adds hooks, which wrap each statement the builder runs.
+func (b *Builder) WithHooks(hs ...dispatch.Hook) *Builder {
+	b.hooks = append(b.hooks, hs...)
+	return b
+}
+
 func (b *Builder) Exec(e dispatch.Execer) (sql.Result, error) {
-	return dispatch.Exec(b, e)
+	return dispatch.Exec(b, e, b.hooks...)
 }
 
 func (b *Builder) ExecContext(ctx context.Context, e dispatch.ExecCtxer) (sql.Result, error) {
-	return dispatch.ExecContext(ctx, b, e)
+	return dispatch.ExecContext(ctx, b, e, b.hooks...)
 }
@@ -0,0 +1,157 @@
+package view
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/dispatch"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/sel"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/statement"
+)
+
+type Formatter interface {
+	FormatNode(w io.Writer, n ast.Node) error
+}
+
+type CreateBuilder struct {
+	f Formatter
+
+	name        string
+	columns     []string
+	orReplace   bool
+	ifNotExists bool
+	as          *sel.Builder
+
+	hooks []dispatch.Hook
+}
+
+func NewCreateBuilder(f Formatter, name string) *CreateBuilder {
+	return &CreateBuilder{
+		f:    f,
+		name: name,
+	}
+}
+
+// OrReplace replaces the view if it already exists (MySQL only).
+func (b *CreateBuilder) OrReplace() *CreateBuilder {
+	b.orReplace = true
+	return b
+}
+
+// IfNotExists leaves an existing view in place instead of failing (SQLite only).
+func (b *CreateBuilder) IfNotExists() *CreateBuilder {
+	b.ifNotExists = true
+	return b
+}
+
+// Columns names the view's columns. Without it, the columns are named after the select list.
+func (b *CreateBuilder) Columns(cols ...string) *CreateBuilder {
+	b.columns = append(b.columns, cols...)
+	return b
+}
+
+// As sets the query which defines the view. Views can't be parameterized, so the query must not
+// contain any placeholders (e.g. from filter.Equals).
+func (b *CreateBuilder) As(q *sel.Builder) *CreateBuilder {
+	b.as = q
+	return b
+}
+
+func (b *CreateBuilder) Build() (statement.Statement, error) {
+	if b.as == nil {
+		return statement.Statement{}, &ast.BuildError{
+			Clause: `CREATE VIEW`,
+			Reason: `must provide a query to create a view`,
+		}
+	}
+
+	cv := ast.NewCreateView(b.name, b.as.IntoSelect())
+	cv.OrReplace = b.orReplace
+	cv.IfNotExists = b.ifNotExists
+	for _, col := range b.columns {
+		cv.Columns = append(cv.Columns, ast.NewIdentifier(col))
+	}
+
+	if len(ast.GetArgs(cv)) > 0 {
+		return statement.Statement{}, &ast.BuildError{
+			Clause: `CREATE VIEW`,
+			Node:   cv,
+			Reason: `views cannot contain placeholders; the view's query must not have arguments`,
+		}
+	}
+
+	sb := &strings.Builder{}
+	if err := b.f.FormatNode(sb, cv); err != nil {
+		return statement.Statement{}, err
+	}
+
+	return statement.Statement{
+		Stmt: sb.String(),
+	}, nil
+}
+
+// WithHooks adds hooks, which wrap each statement the builder runs.
+func (b *CreateBuilder) WithHooks(hs ...dispatch.Hook) *CreateBuilder {
+	b.hooks = append(b.hooks, hs...)
+	return b
+}
+
+func (b *CreateBuilder) Exec(e dispatch.Execer) (sql.Result, error) {
+	return dispatch.Exec(b, e, b.hooks...)
+}
+
+func (b *CreateBuilder) ExecContext(ctx context.Context, e dispatch.ExecCtxer) (sql.Result, error) {
+	return dispatch.ExecContext(ctx, b, e, b.hooks...)
+}
+
+type DropBuilder struct {
+	f Formatter
+
+	name     string
+	ifExists bool
+
+	hooks []dispatch.Hook
+}
+
+func NewDropBuilder(f Formatter, name string) *DropBuilder {
+	return &DropBuilder{
+		f:    f,
+		name: name,
+	}
+}
+
+func (b *DropBuilder) IfExists() *DropBuilder {
+	b.ifExists = true
+	return b
+}
+
+func (b *DropBuilder) Build() (statement.Statement, error) {
+	dv := ast.NewDropView(b.name)
+	dv.IfExists = b.ifExists
+
+	sb := &strings.Builder{}
+	if err := b.f.FormatNode(sb, dv); err != nil {
+		return statement.Statement{}, err
+	}
+
+	return statement.Statement{
+		Stmt: sb.String(),
+	}, nil
+}
+
+// WithHooks adds hooks, which wrap each statement the builder runs.
+func (b *DropBuilder) WithHooks(hs ...dispatch.Hook) *DropBuilder {
+	b.hooks = append(b.hooks, hs...)
+	return b
+}
+
+func (b *DropBuilder) Exec(e dispatch.Execer) (sql.Result, error) {
+	return dispatch.Exec(b, e, b.hooks...)
+}
+
+func (b *DropBuilder) ExecContext(ctx context.Context, e dispatch.ExecCtxer) (sql.Result, error) {
+	return dispatch.ExecContext(ctx, b, e, b.hooks...)
+}
@@ -0,0 +1,86 @@
+// Package window builds window specifications for window functions, e.g.
+//
+//	functions.RowNumber().Over(window.Over().PartitionBy(column.Named("user_id")).OrderBy(filter.OrderDesc("created_at")))
+package window
+
+import (
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/filter"
+	"github.com/cszczepaniak/go-sqlbuilder/sqlbuilder/internal/ast"
+)
+
+// Builder builds a window: the partitions, ordering and frame a window function is evaluated over.
+type Builder struct {
+	partitionBy []ast.IntoExpr
+	orders      []filter.Order
+	frame       *ast.WindowFrame
+}
+
+// Over starts an empty window, which is the whole result set.
+func Over() *Builder {
+	return &Builder{}
+}
+
+// PartitionBy splits the rows into partitions with equal values of es. The window function is evaluated
+// separately for each partition.
+func (b *Builder) PartitionBy(es ...ast.IntoExpr) *Builder {
+	b.partitionBy = append(b.partitionBy, es...)
+	return b
+}
+
+// OrderBy orders the rows within each partition.
+func (b *Builder) OrderBy(os ...filter.Order) *Builder {
+	b.orders = append(b.orders, os...)
+	return b
+}
+
+// Rows limits the window to a frame of rows around the current row, e.g. Rows(Preceding(2),
+// CurrentRow()) for a three-row moving window.
+func (b *Builder) Rows(start, end Bound) *Builder {
+	b.frame = &ast.WindowFrame{Unit: ast.FrameRows, Start: start.b, End: end.b}
+	return b
+}
+
+// Range limits the window to the rows whose ORDER BY value is within a range of the current row's.
+func (b *Builder) Range(start, end Bound) *Builder {
+	b.frame = &ast.WindowFrame{Unit: ast.FrameRange, Start: start.b, End: end.b}
+	return b
+}
+
+func (b *Builder) IntoSpec() *ast.WindowSpec {
+	spec := &ast.WindowSpec{
+		PartitionBy: ast.IntoExprs(b.partitionBy...),
+		Frame:       b.frame,
+	}
+	if len(b.orders) > 0 {
+		spec.OrderBy = &ast.OrderBy{}
+		for _, o := range b.orders {
+			spec.OrderBy.Orders = append(spec.OrderBy.Orders, ast.NewOrder(o.ToASTExpr(), o.Direction.ToASTDirection()))
+		}
+	}
+	return spec
+}
+
+// Bound is one end of a window frame.
+type Bound struct {
+	b ast.FrameBound
+}
+
+func UnboundedPreceding() Bound {
+	return Bound{ast.FrameBound{Kind: ast.BoundUnboundedPreceding}}
+}
+
+func Preceding(n int) Bound {
+	return Bound{ast.FrameBound{Kind: ast.BoundPreceding, Offset: n}}
+}
+
+func CurrentRow() Bound {
+	return Bound{ast.FrameBound{Kind: ast.BoundCurrentRow}}
+}
+
+func Following(n int) Bound {
+	return Bound{ast.FrameBound{Kind: ast.BoundFollowing, Offset: n}}
+}
+
+func UnboundedFollowing() Bound {
+	return Bound{ast.FrameBound{Kind: ast.BoundUnboundedFollowing}}
+}